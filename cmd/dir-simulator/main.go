@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubarydz/dir-simulator/shell"
+	"github.com/kubarydz/dir-simulator/vfs"
+	"golang.org/x/term"
+)
+
+func main() {
+	inputFilename := flag.String("input", "input.txt", "input file, - for standard input")
+	outputFilename := flag.String("output", "output.txt", "output file, - for standard output")
+	onError := flag.String("on-error", "continue", "what to do when a command fails: continue, stop or strict")
+	stateFilename := flag.String("state", "", "file with filesystem state to start from")
+	saveStateFilename := flag.String("save-state", "", "file to save final filesystem state to")
+	fromDir := flag.String("from-dir", "", "directory on disk to import as initial state, it is only read")
+	followSymlinks := flag.Bool("follow-symlinks", false, "follow symbolic links when importing, otherwise they are skipped")
+	skipDenied := flag.Bool("skip-denied", false, "skip entries without read permission when importing, otherwise import fails")
+	maxDepth := flag.Int("max-depth", -1, "maximal depth of imported directories, negative means no limit")
+	withContent := flag.Bool("with-content", false, "import content of the files, otherwise files are imported empty")
+	exportTarget := flag.String("export", "", "directory, .zip, .tar or .tar.gz archive to write final filesystem to")
+	interactive := flag.Bool("i", false, "interactive mode, commands are read from the terminal")
+	asciiTree := flag.Bool("ascii", false, "draw trees with ASCII characters, as tree /a does")
+	width := flag.Int("width", 80, "width of the terminal, lines of dir listings are wrapped to it")
+	columnWidth := flag.Int("column-width", 8, "names in dir listings start at multiples of it")
+	strictColumns := flag.Bool("strict-columns", false, "report lines not in the fixed-column format as errors, e.g. \"cd sub1\" instead of \"cd      sub1\"")
+	withEnv := flag.Bool("env", false, "start with variables of the process environment, e.g. %HOME%")
+	echoComments := flag.Bool("echo-comments", false, "echo rem and # comment lines to the output, otherwise they are skipped")
+	flag.Parse()
+
+	policy, err := shell.ParseErrorPolicy(*onError)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v: %v\n", err, *onError)
+		os.Exit(2)
+	}
+
+	if *stateFilename != "" && *fromDir != "" {
+		fmt.Fprintln(os.Stderr, "cannot use both -state and -from-dir")
+		os.Exit(2)
+	}
+	if *width < 1 || *columnWidth < 1 {
+		fmt.Fprintln(os.Stderr, "-width and -column-width must be positive")
+		os.Exit(2)
+	}
+
+	fs := vfs.New()
+	if *stateFilename != "" {
+		fs, err = loadState(*stateFilename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot load state from %v, error: %v\n", *stateFilename, err)
+			os.Exit(1)
+		}
+	}
+	if *fromDir != "" {
+		fs, err = vfs.Import(*fromDir, vfs.ImportOptions{
+			FollowSymlinks: *followSymlinks,
+			SkipDenied:     *skipDenied,
+			MaxDepth:       *maxDepth,
+			WithContent:    *withContent,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot import %v, error: %v\n", *fromDir, err)
+			os.Exit(1)
+		}
+	}
+
+	// pipes are used unless files are given explicitly
+	input := *inputFilename
+	if !isFlagSet("input") && isPipe(os.Stdin) {
+		input = "-"
+	}
+	output := *outputFilename
+	if !isFlagSet("output") && isPipe(os.Stdout) {
+		output = "-"
+	}
+
+	opts := shell.Options{
+		ASCIITree:     *asciiTree,
+		Width:         *width,
+		ColumnWidth:   *columnWidth,
+		StrictColumns: *strictColumns,
+		EchoComments:  *echoComments,
+		Scripts:       os.DirFS(scriptsDir(input)),
+	}
+	if *withEnv {
+		opts.Variables = environment()
+	}
+	sh := shell.New(fs, opts)
+	var processErr error
+	switch {
+	case (*interactive || isInteractiveByDefault(input)) && term.IsTerminal(int(os.Stdin.Fd())):
+		processErr = sh.InteractiveTerminal(os.Stdin, os.Stdout, makeStdinRaw)
+	case *interactive:
+		processErr = sh.Interactive(os.Stdin, os.Stdout)
+	default:
+		processErr = processCommands(sh, input, output, policy)
+	}
+
+	if *saveStateFilename != "" {
+		if err := saveState(fs, *saveStateFilename); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot save state to %v, error: %v\n", *saveStateFilename, err)
+			os.Exit(1)
+		}
+	}
+	if processErr != nil {
+		fmt.Fprintln(os.Stderr, processErr)
+		os.Exit(1)
+	}
+	if *exportTarget != "" {
+		if err := fs.Export(*exportTarget); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot export to %v, error: %v\n", *exportTarget, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// without explicit input the session is interactive
+// when started from a terminal and the default input file doesn't exist
+func isInteractiveByDefault(inputFilename string) bool {
+	if isFlagSet("input") {
+		return false
+	}
+	if _, err := os.Stat(inputFilename); err == nil {
+		return false
+	}
+	stat, err := os.Stdin.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// switches the terminal to raw mode for editing lines, returns function restoring the previous mode
+func makeStdinRaw() (func(), error) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	return func() { term.Restore(fd, state) }, nil
+}
+
+// scripts run by call are looked up next to the input file, or in the working directory
+func scriptsDir(inputFilename string) string {
+	if inputFilename == "-" {
+		return "."
+	}
+	return filepath.Dir(inputFilename)
+}
+
+// variables of the process environment by name
+func environment() map[string]string {
+	variables := map[string]string{}
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		// Windows keeps per-drive directories under names starting with =
+		if name != "" {
+			variables[name] = value
+		}
+	}
+	return variables
+}
+
+func isPipe(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeNamedPipe != 0
+}
+
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// executes commands from the input file on the filesystem and writes their output to the output file
+// "-" as a filename stands for standard input or standard output
+// returns error if the files cannot be opened, written or closed or, in strict mode, error of the first failed command
+func processCommands(sh *shell.Shell, inputFilename, outputFilename string, policy shell.ErrorPolicy) error {
+	var input io.Reader = os.Stdin
+	if inputFilename != "-" {
+		inputFile, err := os.Open(inputFilename)
+		if err != nil {
+			return fmt.Errorf("cannot open input file: %w", err)
+		}
+		defer inputFile.Close()
+		input = inputFile
+	}
+
+	if outputFilename == "-" {
+		return sh.Run(input, os.Stdout, policy)
+	}
+	outputFile, err := os.OpenFile(outputFilename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open output file: %w", err)
+	}
+	if err := sh.Run(input, outputFile, policy); err != nil {
+		outputFile.Close()
+		return err
+	}
+	if err := outputFile.Close(); err != nil {
+		return fmt.Errorf("cannot close output file: %w", err)
+	}
+	return nil
+}
+
+func loadState(filename string) (*vfs.Filesystem, error) {
+	stateFile, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer stateFile.Close()
+	return vfs.Load(bufio.NewReader(stateFile))
+}
+
+func saveState(fs *vfs.Filesystem, filename string) error {
+	stateFile, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := fs.SaveState(stateFile); err != nil {
+		stateFile.Close()
+		return err
+	}
+	return stateFile.Close()
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/kubarydz/dir-simulator/shell"
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+func TestProcessCommands(t *testing.T) {
+	tests := []struct {
+		name                   string
+		inputFilename          string
+		outputFilename         string
+		expectedOutputFilename string
+		stateFilename          string
+		opts                   shell.Options
+		policy                 shell.ErrorPolicy
+		expectedErrLine        int
+	}{
+		{
+			name:                   "test dir, mkdir, up and cd",
+			inputFilename:          "../../resources/test_input1.txt",
+			outputFilename:         "../../resources/output1.txt",
+			expectedOutputFilename: "../../resources/test_output1.txt",
+		},
+		{
+			name:                   "fixed-column input with strict columns",
+			inputFilename:          "../../resources/test_input1.txt",
+			outputFilename:         "../../resources/output1_strict.txt",
+			expectedOutputFilename: "../../resources/test_output1.txt",
+			opts:                   shell.Options{StrictColumns: true},
+		},
+		{
+			name:                   "test tree and mv",
+			inputFilename:          "../../resources/test_input2.txt",
+			outputFilename:         "../../resources/output2.txt",
+			expectedOutputFilename: "../../resources/test_output2.txt",
+		},
+		{
+			name:                   "tree and mv with strict columns",
+			inputFilename:          "../../resources/test_input2.txt",
+			outputFilename:         "../../resources/output2_strict.txt",
+			expectedOutputFilename: "../../resources/test_output2.txt",
+			opts:                   shell.Options{StrictColumns: true},
+		},
+		{
+			name:                   "start from saved state",
+			inputFilename:          "../../resources/test_input4.txt",
+			outputFilename:         "../../resources/output4.txt",
+			expectedOutputFilename: "../../resources/test_output4.txt",
+			stateFilename:          "../../resources/test_state4.json",
+		},
+		{
+			name:                   "script with variables and comments",
+			inputFilename:          "../../resources/test_input5.txt",
+			outputFilename:         "../../resources/output5.txt",
+			expectedOutputFilename: "../../resources/test_output5.txt",
+			opts:                   shell.Options{StrictColumns: true},
+		},
+		{
+			name:                   "script with statements calling another script",
+			inputFilename:          "../../resources/test_input6.txt",
+			outputFilename:         "../../resources/output6.txt",
+			expectedOutputFilename: "../../resources/test_output6.txt",
+			opts:                   shell.Options{StrictColumns: true, Scripts: os.DirFS("../../resources")},
+		},
+		{
+			name:                   "continue on errors",
+			inputFilename:          "../../resources/test_input3.txt",
+			outputFilename:         "../../resources/output3.txt",
+			expectedOutputFilename: "../../resources/test_output3.txt",
+			policy:                 shell.ContinueOnError,
+		},
+		{
+			name:                   "stop on first error",
+			inputFilename:          "../../resources/test_input3.txt",
+			outputFilename:         "../../resources/output3_stop.txt",
+			expectedOutputFilename: "../../resources/test_output3_stop.txt",
+			policy:                 shell.StopOnError,
+		},
+		{
+			name:                   "strict mode reports line of first error",
+			inputFilename:          "../../resources/test_input3.txt",
+			outputFilename:         "../../resources/output3_strict.txt",
+			expectedOutputFilename: "../../resources/test_output3_stop.txt",
+			policy:                 shell.StrictOnError,
+			expectedErrLine:        2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				os.Remove(tt.outputFilename)
+			})
+			fs := vfs.New()
+			if tt.stateFilename != "" {
+				var err error
+				fs, err = loadState(tt.stateFilename)
+				if err != nil {
+					t.Fatalf("cannot load state: %v", err)
+				}
+			}
+			err := processCommands(shell.New(fs, tt.opts), tt.inputFilename, tt.outputFilename, tt.policy)
+			var lineErr *shell.LineError
+			if tt.expectedErrLine == 0 && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectedErrLine != 0 && (!errors.As(err, &lineErr) || lineErr.Line != tt.expectedErrLine) {
+				t.Fatalf("expected error in line %d, got: %v", tt.expectedErrLine, err)
+			}
+
+			if !deepCompare(tt.expectedOutputFilename, tt.outputFilename) {
+				t.Fatal("output doesn't match expected file")
+			}
+		})
+	}
+}
+
+const chunckSize = 64000
+
+// compare files line by line
+// failfast if the size differs
+func deepCompare(file1, file2 string) bool {
+	f1s, err := os.Stat(file1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	f2s, err := os.Stat(file2)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if f1s.Size() != f2s.Size() {
+		return false
+	}
+
+	f1, err := os.Open(file1)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	f2, err := os.Open(file2)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for {
+		b1 := make([]byte, chunckSize)
+		_, err1 := f1.Read(b1)
+
+		b2 := make([]byte, chunckSize)
+		_, err2 := f2.Read(b2)
+
+		if err1 != nil || err2 != nil {
+			if err1 == io.EOF && err2 == io.EOF {
+				return true
+			} else if err1 == io.EOF && err2 == io.EOF {
+				return false
+			} else {
+				log.Fatal(err1, err2)
+			}
+		}
+
+		if !bytes.Equal(b1, b2) {
+			return false
+		}
+	}
+}
+
+func TestProcessCommandsMissingInput(t *testing.T) {
+	t.Parallel()
+	err := processCommands(shell.New(vfs.New(), shell.Options{}), "../../resources/does_not_exist.txt", "../../resources/output_missing.txt", shell.ContinueOnError)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", os.ErrNotExist, err)
+	}
+}
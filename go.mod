@@ -2,4 +2,9 @@ module github.com/kubarydz/dir-simulator
 
 go 1.20
 
-require github.com/google/go-cmp v0.5.9
+require (
+	github.com/google/go-cmp v0.5.9
+	golang.org/x/term v0.15.0
+)
+
+require golang.org/x/sys v0.15.0 // indirect
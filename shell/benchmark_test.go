@@ -0,0 +1,49 @@
+package shell
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+var benchmarkSizes = []int{100_000, 1_000_000}
+
+// creates filesystem with n directories, each with up to 10 subdirectories
+func createLargeFilesystem(n int) *vfs.Filesystem {
+	fs := vfs.New()
+	paths := []string{"root"}
+	for i := 1; i < n; i++ {
+		path := fmt.Sprintf("%s\\sub%d", paths[(i-1)/10], i)
+		fs.AddSubdir(path)
+		paths = append(paths, path)
+	}
+	return fs
+}
+
+func BenchmarkWriteTree(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("nodes=%d", n), func(b *testing.B) {
+			fs := createLargeFilesystem(n)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				WriteTree(io.Discard, fs.Root(), TreeOptions{})
+			}
+		})
+	}
+}
+
+func BenchmarkRenderTree(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("nodes=%d", n), func(b *testing.B) {
+			fs := createLargeFilesystem(n)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				RenderTree(fs.Root(), TreeOptions{})
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+var (
+	ErrUnknownCommand         = errors.New("Command not known")
+	ErrWrongNumberOfArguments = errors.New("Command has wrong number of arguments")
+	ErrInvalidSwitch          = errors.New("Invalid switch")
+)
+
+// built-in command backed by a handler function
+// handler returns output lines, streamHandler writes them, only one of them is set
+type command struct {
+	name          string
+	aliases       []string
+	usage         string
+	minArgs       int
+	maxArgs       int
+	handler       func(fs *vfs.Filesystem, args []string) ([]string, error)
+	streamHandler func(w io.Writer, fs *vfs.Filesystem, args []string) error
+}
+
+func (c *command) Name() string {
+	return c.name
+}
+
+func (c *command) Aliases() []string {
+	return c.aliases
+}
+
+func (c *command) Arity() (int, int) {
+	return c.minArgs, c.maxArgs
+}
+
+func (c *command) Usage() string {
+	return c.usage
+}
+
+func (c *command) Execute(fs *vfs.Filesystem, args []string) ([]string, error) {
+	if c.handler != nil {
+		return c.handler(fs, args)
+	}
+	var buf bytes.Buffer
+	err := c.streamHandler(&buf, fs, args)
+	return splitLines(buf.String()), err
+}
+
+func (c *command) ExecuteTo(w io.Writer, fs *vfs.Filesystem, args []string) error {
+	if c.streamHandler != nil {
+		return c.streamHandler(w, fs, args)
+	}
+	output, err := c.handler(fs, args)
+	if writeErr := writeLines(w, output); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+func builtinCommands(s *Shell) []Command {
+	return []Command{
+		&command{
+			name:    "dir",
+			usage:   "dir [path] [/b | /w | /l]",
+			maxArgs: -1,
+			handler: s.handleDir,
+		},
+		&command{
+			name:    "mkdir",
+			aliases: []string{"md"},
+			usage:   "mkdir <path>",
+			minArgs: 1,
+			maxArgs: 1,
+			handler: handleMkdir,
+		},
+		&command{
+			name:    "up",
+			usage:   "up",
+			handler: handleUp,
+		},
+		&command{
+			name:    "cd",
+			aliases: []string{"chdir"},
+			usage:   "cd <path>",
+			minArgs: 1,
+			maxArgs: 1,
+			handler: handleCd,
+		},
+		&command{
+			name:          "tree",
+			usage:         "tree [path] [/f | /d] [/l depth] [/p pattern] [/s] [/a]",
+			maxArgs:       -1,
+			streamHandler: s.handleTree,
+		},
+		&command{
+			name:    "mv",
+			aliases: []string{"move"},
+			usage:   "mv <from> <to>",
+			minArgs: 2,
+			maxArgs: 2,
+			handler: handleMv,
+		},
+		&command{
+			name:    "touch",
+			usage:   "touch <path>",
+			minArgs: 1,
+			maxArgs: 1,
+			handler: handleTouch,
+		},
+		&command{
+			name:    "type",
+			aliases: []string{"cat"},
+			usage:   "type <path>",
+			minArgs: 1,
+			maxArgs: 1,
+			handler: handleType,
+		},
+		&command{
+			name:    "append",
+			usage:   "append <path> <text>",
+			minArgs: 2,
+			maxArgs: -1,
+			handler: handleAppend,
+		},
+		&command{
+			name:    "rmdir",
+			aliases: []string{"rd"},
+			usage:   "rmdir [/s] <path>",
+			minArgs: 1,
+			maxArgs: 2,
+			handler: handleRmdir,
+		},
+		&command{
+			name:    "deltree",
+			usage:   "deltree <path>",
+			minArgs: 1,
+			maxArgs: 1,
+			handler: handleDeltree,
+		},
+		&command{
+			name:    "del",
+			aliases: []string{"erase"},
+			usage:   "del <path>",
+			minArgs: 1,
+			maxArgs: 1,
+			handler: handleDel,
+		},
+		&command{
+			name:    "set",
+			usage:   "set [name[=value]]",
+			maxArgs: -1,
+			handler: s.handleSet,
+		},
+		&command{
+			name:    "copy",
+			aliases: []string{"cp", "xcopy"},
+			usage:   "copy <from> <to> [/y]",
+			minArgs: 2,
+			maxArgs: 3,
+			handler: handleCopy,
+		},
+	}
+}
+
+func handleMkdir(fs *vfs.Filesystem, args []string) ([]string, error) {
+	return nil, fs.AddSubdir(args[0])
+}
+
+func handleUp(fs *vfs.Filesystem, args []string) ([]string, error) {
+	return nil, fs.Up()
+}
+
+// changes current directory, pattern with wildcards must match exactly one directory
+func handleCd(fs *vfs.Filesystem, args []string) ([]string, error) {
+	path, err := expandDir(fs, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return nil, fs.Cd(path)
+}
+
+func handleTouch(fs *vfs.Filesystem, args []string) ([]string, error) {
+	return nil, fs.Touch(args[0])
+}
+
+// prints content of the file line by line
+func handleType(fs *vfs.Filesystem, args []string) ([]string, error) {
+	f, err := fs.ResolveFile(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if f.Size() == 0 {
+		return nil, nil
+	}
+	return strings.Split(strings.TrimSuffix(string(f.Content()), "\n"), "\n"), nil
+}
+
+// appends remaining arguments as a new line of the file
+func handleAppend(fs *vfs.Filesystem, args []string) ([]string, error) {
+	line := strings.Join(args[1:], " ") + "\n"
+	return nil, fs.AppendFile(args[0], []byte(line))
+}
+
+// moves or renames directory, with wildcards moves each matching directory in order of names
+// several directories can be moved only into an existing one, which is skipped if it matches too
+// stops at the first directory which cannot be moved
+func handleMv(fs *vfs.Filesystem, args []string) ([]string, error) {
+	sources, err := expandDirs(fs, args[0])
+	if err != nil {
+		return nil, err
+	}
+	to, err := expandDir(fs, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 1 {
+		return nil, fs.Mv(sources[0], to)
+	}
+	destination, err := fs.ResolveDir(to)
+	if err != nil {
+		return nil, err
+	}
+	for _, from := range sources {
+		if dir, _ := fs.ResolveDir(from); dir == destination {
+			continue
+		}
+		if err := fs.Mv(from, to); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// removes empty directory, with /s removes also its content
+// with wildcards removes each matching directory, stops at the first which cannot be removed
+func handleRmdir(fs *vfs.Filesystem, args []string) ([]string, error) {
+	recursive := false
+	path := ""
+	for _, arg := range args {
+		switch {
+		case strings.EqualFold(arg, "/s"):
+			recursive = true
+		case strings.HasPrefix(arg, "/"):
+			return nil, ErrInvalidSwitch
+		case path != "":
+			return nil, ErrWrongNumberOfArguments
+		default:
+			path = arg
+		}
+	}
+	if path == "" {
+		return nil, ErrWrongNumberOfArguments
+	}
+	return nil, removeDirs(fs, path, recursive)
+}
+
+func handleDeltree(fs *vfs.Filesystem, args []string) ([]string, error) {
+	return nil, removeDirs(fs, args[0], true)
+}
+
+func removeDirs(fs *vfs.Filesystem, path string, recursive bool) error {
+	dirs, err := expandDirs(fs, path)
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := fs.Rmdir(dir, recursive); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func handleDel(fs *vfs.Filesystem, args []string) ([]string, error) {
+	return nil, fs.RemoveFile(args[0])
+}
+
+// copies file or directory with its content, with /y replaces existing destination
+func handleCopy(fs *vfs.Filesystem, args []string) ([]string, error) {
+	overwrite := false
+	paths := []string{}
+	for _, arg := range args {
+		switch {
+		case strings.EqualFold(arg, "/y"):
+			overwrite = true
+		case strings.HasPrefix(arg, "/"):
+			return nil, ErrInvalidSwitch
+		default:
+			paths = append(paths, arg)
+		}
+	}
+	if len(paths) != 2 {
+		return nil, ErrWrongNumberOfArguments
+	}
+	return nil, fs.Copy(paths[0], paths[1], overwrite)
+}
@@ -0,0 +1,1654 @@
+package shell
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+func TestHandleDir(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		cmdArg         string
+		fs             func() *vfs.Filesystem
+		expectedOutput []string
+		expectedErr    error
+	}{
+		{
+			name: "root with no subdirs",
+			fs:   vfs.New,
+			expectedOutput: []string{
+				"Directory of root:",
+				"No subdirectories",
+			},
+		},
+		{
+			name: "root with subdirs",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				return fs
+			},
+			expectedOutput: []string{
+				"Directory of root:",
+				"sub1    sub2",
+			},
+		},
+		{
+			name: "inside dir with subdirs",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.Cd(fs.Current().Subdirs()[0].Name())
+				fs.AddSubdir("sub4")
+				fs.AddSubdir("sub3")
+				return fs
+			},
+			expectedOutput: []string{
+				"Directory of root\\sub1:",
+				"sub3    sub4",
+			},
+		},
+		{
+			name: "inside dir without subdirs",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.Cd(fs.Current().Subdirs()[0].Name())
+				return fs
+			},
+			expectedOutput: []string{
+				"Directory of root\\sub1:",
+				"No subdirectories",
+			},
+		},
+		{
+			name:   "sibling by relative path",
+			cmdArg: "..\\sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2\\sub3")
+				fs.Cd("sub1")
+				return fs
+			},
+			expectedOutput: []string{
+				"Directory of root\\sub2:",
+				"sub3",
+			},
+		},
+		{
+			name:           "missing directory",
+			cmdArg:         "sub1\\sub2",
+			fs:             vfs.New,
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrSubdirDoesNotExist,
+		},
+		{
+			name: "files listed after subdirs",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Touch("file2.txt")
+				fs.AddSubdir("sub2")
+				fs.Touch("file1.txt")
+				return fs
+			},
+			expectedOutput: []string{
+				"Directory of root:",
+				"sub1    sub2",
+				"Files:",
+				"file1.txt       file2.txt",
+			},
+		},
+		{
+			name: "only files",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.Touch("file1.txt")
+				return fs
+			},
+			expectedOutput: []string{
+				"Directory of root:",
+				"No subdirectories",
+				"Files:",
+				"file1.txt",
+			},
+		},
+		{
+			name: "exactly 10 subdirs should not wrap output",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.AddSubdir("sub4")
+				fs.AddSubdir("sub3")
+				fs.AddSubdir("sub5")
+				fs.AddSubdir("sub6")
+				fs.AddSubdir("sub7")
+				fs.AddSubdir("sub8")
+				fs.AddSubdir("sub9")
+				fs.AddSubdir("sub10")
+				return fs
+			},
+			expectedOutput: []string{
+				"Directory of root:",
+				"sub1    sub10   sub2    sub3    sub4    sub5    sub6    sub7    sub8    sub9",
+			},
+		},
+		{
+			name: "more than 10 subdirs",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.AddSubdir("sub4")
+				fs.AddSubdir("sub3")
+				fs.AddSubdir("sub5")
+				fs.AddSubdir("sub6")
+				fs.AddSubdir("sub7")
+				fs.AddSubdir("sub8")
+				fs.AddSubdir("sub9")
+				fs.AddSubdir("sub10")
+				fs.AddSubdir("sub11")
+				fs.AddSubdir("sub12")
+				return fs
+			},
+			expectedOutput: []string{
+				"Directory of root:",
+				"sub1    sub10   sub11   sub12   sub2    sub3    sub4    sub5    sub6    sub7",
+				"sub8    sub9",
+			},
+		},
+		{
+			name: "long subdir names",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("longsubname1")
+				fs.AddSubdir("longsubname2")
+				fs.AddSubdir("longsubname3")
+				fs.AddSubdir("longsubname4")
+				fs.AddSubdir("longsubname5")
+				fs.AddSubdir("longsubname6")
+				return fs
+			},
+			expectedOutput: []string{
+				"Directory of root:",
+				"longsubname1    longsubname2    longsubname3    longsubname4    longsubname5",
+				"longsubname6",
+			},
+		},
+		{
+			name: "subdir names with length of column width",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("longsub1")
+				fs.AddSubdir("longsub2")
+				fs.AddSubdir("longsub3")
+				fs.AddSubdir("longsub4")
+				fs.AddSubdir("longsub5")
+				fs.AddSubdir("longsub6")
+				return fs
+			},
+			expectedOutput: []string{
+				"Directory of root:",
+				"longsub1        longsub2        longsub3        longsub4        longsub5",
+				"longsub6",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := execute("dir     "+tt.cmdArg, tt.fs())
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleMkdir(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		cmdArg           string
+		fs               func() *vfs.Filesystem
+		expectedOutput   []string
+		expectedErr      error
+		expectedSubNames []string
+		// whole tree with files, checked if set
+		expectedTree []string
+	}{
+		{
+			name:             "make new dir when no subdir exists",
+			cmdArg:           "sub1",
+			fs:               vfs.New,
+			expectedOutput:   nil,
+			expectedSubNames: []string{"sub1"},
+		},
+		{
+			name:   "make new dir when subdirs exist",
+			cmdArg: "sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub2")
+				fs.AddSubdir("sub3")
+				return fs
+			},
+			expectedOutput:   nil,
+			expectedSubNames: []string{"sub1", "sub2", "sub3"},
+		},
+		{
+			name:   "cannot make subdir with same name as existing",
+			cmdArg: "sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				return fs
+			},
+			expectedOutput:   nil,
+			expectedErr:      vfs.ErrSubdirAlreadyExists,
+			expectedSubNames: []string{"sub1"},
+		},
+		{
+			name:             "make intermediate dirs",
+			cmdArg:           "sub1\\sub2\\sub3",
+			fs:               vfs.New,
+			expectedOutput:   nil,
+			expectedSubNames: []string{"sub1"},
+		},
+		{
+			name:   "make dir in existing subdir",
+			cmdArg: "sub1\\sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				return fs
+			},
+			expectedOutput:   nil,
+			expectedSubNames: []string{"sub1"},
+		},
+		{
+			name:   "make dir in sibling",
+			cmdArg: "..\\sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Cd("sub1")
+				return fs
+			},
+			expectedOutput:   nil,
+			expectedSubNames: []string{},
+		},
+		{
+			name:   "cannot make existing nested dir",
+			cmdArg: "root\\sub1\\sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub2")
+				return fs
+			},
+			expectedOutput:   nil,
+			expectedErr:      vfs.ErrSubdirAlreadyExists,
+			expectedSubNames: []string{"sub1"},
+		},
+		{
+			name:   "root name alone refers to existing root",
+			cmdArg: "root",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Cd("sub1")
+				return fs
+			},
+			expectedOutput:   nil,
+			expectedErr:      vfs.ErrSubdirAlreadyExists,
+			expectedSubNames: []string{},
+		},
+		{
+			name:   "make dir named as root in subdir",
+			cmdArg: ".\\root",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Cd("sub1")
+				return fs
+			},
+			expectedOutput:   nil,
+			expectedSubNames: []string{"root"},
+		},
+		{
+			name:             "parent of root does not exist",
+			cmdArg:           "a\\..\\..\\q",
+			fs:               vfs.New,
+			expectedOutput:   nil,
+			expectedErr:      vfs.ErrSubdirDoesNotExist,
+			expectedSubNames: []string{},
+		},
+		{
+			name:             "make dirs with parent steps between them",
+			cmdArg:           "sub1\\..\\sub2\\.\\sub3",
+			fs:               vfs.New,
+			expectedOutput:   nil,
+			expectedSubNames: []string{"sub2"},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"└── sub2",
+				"    └── sub3",
+			},
+		},
+		{
+			name:             "invalid name leaves tree unchanged",
+			cmdArg:           "sub1\\sub*",
+			fs:               vfs.New,
+			expectedOutput:   nil,
+			expectedErr:      vfs.ErrInvalidPath,
+			expectedSubNames: []string{},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+			},
+		},
+		{
+			name:             "path above root leaves tree unchanged",
+			cmdArg:           "sub1\\sub2\\..\\..\\..\\sub3",
+			fs:               vfs.New,
+			expectedOutput:   nil,
+			expectedErr:      vfs.ErrSubdirDoesNotExist,
+			expectedSubNames: []string{},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+			},
+		},
+		{
+			name:   "name of existing file leaves tree unchanged",
+			cmdArg: "sub1\\sub2\\..\\a.txt\\sub3",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Touch("sub1\\a.txt")
+				return fs
+			},
+			expectedOutput:   nil,
+			expectedErr:      vfs.ErrFileAlreadyExists,
+			expectedSubNames: []string{"sub1"},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"└── sub1",
+				"    └── a.txt",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := tt.fs()
+			output, err := execute("mkdir   "+tt.cmdArg, fs)
+
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			subDirNames := []string{}
+			for _, subdir := range fs.Current().Subdirs() {
+				subDirNames = append(subDirNames, subdir.Name())
+			}
+			if diff := cmp.Diff(tt.expectedSubNames, subDirNames); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+			if tt.expectedTree == nil {
+				return
+			}
+			tree, _ := execute("tree    \\ /f", fs)
+			if diff := cmp.Diff(tt.expectedTree, tree); diff != "" {
+				t.Fatalf("tree mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleUp(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name               string
+		fs                 func() *vfs.Filesystem
+		expectedOutput     []string
+		expectedErr        error
+		expectedCurrentDir func(fs *vfs.Filesystem) *vfs.Dir
+	}{
+		{
+			name: "move up a dir",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Cd(fs.Current().Subdirs()[0].Name())
+				return fs
+			},
+			expectedOutput: nil,
+			expectedCurrentDir: func(fs *vfs.Filesystem) *vfs.Dir {
+				return fs.Current().Parent()
+			},
+		},
+		{
+			name:           "cannot move up from root",
+			fs:             vfs.New,
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrCannotMoveUpFromRoot,
+			expectedCurrentDir: func(fs *vfs.Filesystem) *vfs.Dir {
+				return fs.Current()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := tt.fs()
+			expectedCurrent := tt.expectedCurrentDir(fs)
+			output, err := execute("up", fs)
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			if expectedCurrent != fs.Current() {
+				t.Fatalf("current dir mismatch:\nwant: %s\ngot: %s", expectedCurrent.Name(), fs.Current().Name())
+			}
+
+		})
+	}
+}
+
+func TestHandleCd(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name               string
+		cmdArg             string
+		fs                 func() *vfs.Filesystem
+		expectedOutput     []string
+		expectedErr        error
+		expectedCurrentDir func(fs *vfs.Filesystem) *vfs.Dir
+	}{
+		{
+			name:   "move to subdir",
+			cmdArg: "sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedCurrentDir: func(fs *vfs.Filesystem) *vfs.Dir {
+				return fs.Current().Subdirs()[0]
+			},
+		},
+		{
+			name:   "cannot move to non existing subdir",
+			cmdArg: "sub3",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrSubdirDoesNotExist,
+			expectedCurrentDir: func(fs *vfs.Filesystem) *vfs.Dir {
+				return fs.Current()
+			},
+		},
+		{
+			name:   "move by absolute path",
+			cmdArg: "root\\sub2\\sub3",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub4")
+				fs.AddSubdir("sub2\\sub3")
+				fs.Cd("sub1\\sub4")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedCurrentDir: func(fs *vfs.Filesystem) *vfs.Dir {
+				return fs.Root().Subdirs()[1].Subdirs()[0]
+			},
+		},
+		{
+			name:   "move by absolute path starting with separator",
+			cmdArg: "\\sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub4")
+				fs.AddSubdir("sub2")
+				fs.Cd("sub1\\sub4")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedCurrentDir: func(fs *vfs.Filesystem) *vfs.Dir {
+				return fs.Root().Subdirs()[1]
+			},
+		},
+		{
+			name:   "move to root",
+			cmdArg: "root",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub4")
+				fs.Cd("sub1\\sub4")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedCurrentDir: func(fs *vfs.Filesystem) *vfs.Dir {
+				return fs.Root()
+			},
+		},
+		{
+			name:   "root is absolute despite subdir named as root",
+			cmdArg: "root",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\root")
+				fs.Cd("sub1")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedCurrentDir: func(fs *vfs.Filesystem) *vfs.Dir {
+				return fs.Root()
+			},
+		},
+		{
+			name:   "relative path through subdir named as root",
+			cmdArg: ".\\root\\sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\root\\sub2")
+				fs.AddSubdir("sub2")
+				fs.Cd("sub1")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedCurrentDir: func(fs *vfs.Filesystem) *vfs.Dir {
+				return fs.Root().Subdirs()[0].Subdirs()[0].Subdirs()[0]
+			},
+		},
+		{
+			name:   "move by multi-level relative path",
+			cmdArg: "..\\..\\sub2\\.",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub4")
+				fs.AddSubdir("sub2")
+				fs.Cd("sub1\\sub4")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedCurrentDir: func(fs *vfs.Filesystem) *vfs.Dir {
+				return fs.Root().Subdirs()[1]
+			},
+		},
+		{
+			name:   "missing intermediate directory",
+			cmdArg: "sub3\\sub4",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub4")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrSubdirDoesNotExist,
+			expectedCurrentDir: func(fs *vfs.Filesystem) *vfs.Dir {
+				return fs.Current()
+			},
+		},
+		{
+			name:           "cannot move above root",
+			cmdArg:         "..",
+			fs:             vfs.New,
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrSubdirDoesNotExist,
+			expectedCurrentDir: func(fs *vfs.Filesystem) *vfs.Dir {
+				return fs.Current()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := tt.fs()
+			expectedCurrent := tt.expectedCurrentDir(fs)
+			output, err := execute("cd      "+tt.cmdArg, fs)
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			if expectedCurrent != fs.Current() {
+				t.Fatalf("current dir mismatch:\nwant: %s\ngot: %s", expectedCurrent.Name(), fs.Current().Name())
+			}
+		})
+	}
+}
+
+func TestHandleMv(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		argFrom        string
+		argTo          string
+		fs             func() *vfs.Filesystem
+		expectedOutput []string
+		expectedErr    error
+	}{
+		{
+			name:           "mv non existing subdir",
+			argFrom:        "nosub",
+			argTo:          "sub1",
+			fs:             vfs.New,
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrSubdirDoesNotExist,
+		},
+		{
+			name:    "rename subdir",
+			argFrom: "sub1",
+			argTo:   "sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				return fs
+			},
+			expectedOutput: nil,
+		},
+		{
+			name:    "rename subdir using relative path",
+			argFrom: "sub1",
+			argTo:   ".\\sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				return fs
+			},
+			expectedOutput: nil,
+		},
+		{
+			name:    "move and rename subdir",
+			argFrom: "sub1",
+			argTo:   "sub2\\sub111",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				return fs
+			},
+			expectedOutput: nil,
+		},
+		{
+			name:    "subdirectory already exists",
+			argFrom: "sub1",
+			argTo:   "sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.Cd("sub2")
+				fs.AddSubdir("sub1")
+				fs.Cd("\\")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrSubdirAlreadyExists,
+		},
+		{
+			name:    "move from nested path to absolute path",
+			argFrom: "sub2\\sub3",
+			argTo:   "\\sub1\\sub4",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2\\sub3")
+				return fs
+			},
+			expectedOutput: nil,
+		},
+		{
+			name:    "cannot move into own subdir",
+			argFrom: "sub1",
+			argTo:   "sub1\\sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub2")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrMoveIntoItself,
+		},
+		{
+			name:    "cannot move into itself under new name",
+			argFrom: "sub1",
+			argTo:   "sub1\\sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrMoveIntoItself,
+		},
+		{
+			name:    "cannot move into nested descendant",
+			argFrom: "\\sub1",
+			argTo:   "\\sub1\\sub2\\sub3\\sub4",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub2\\sub3")
+				fs.Cd("sub1\\sub2")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrMoveIntoItself,
+		},
+		{
+			name:    "file with destination name already exists",
+			argFrom: "sub1",
+			argTo:   "file1.txt",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Touch("file1.txt")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrFileAlreadyExists,
+		},
+		{
+			name:    "cannot move to illegal path",
+			argFrom: "sub1",
+			argTo:   "..",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Cd("\\")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrSubdirDoesNotExist,
+		},
+		{
+			name:    "cannot move to illegal intermediate path",
+			argFrom: "sub1",
+			argTo:   "sub2\\..\\..\\root",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.Cd("sub2")
+				fs.AddSubdir("sub1")
+				fs.Cd("\\")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrSubdirDoesNotExist,
+		},
+		{
+			name:    "parent of destination does not exist",
+			argFrom: "sub1",
+			argTo:   "sub2\\sub3\\sub4",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrSubdirDoesNotExist,
+		},
+		{
+			name:    "complicated relative path",
+			argFrom: "sub1",
+			argTo:   "sub2\\.\\..\\sub2\\.\\.\\sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.Cd("sub2")
+				fs.AddSubdir("sub1")
+				fs.Cd("\\")
+				return fs
+			},
+			expectedOutput: nil,
+		},
+		{
+			name:    "move to current dir",
+			argFrom: "sub1",
+			argTo:   ".",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				return fs
+			},
+			expectedOutput: nil,
+		},
+		{
+			name:    "move to current dir with same name specified",
+			argFrom: "sub1",
+			argTo:   "sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				return fs
+			},
+			expectedOutput: nil,
+		},
+		{
+			name:    "move to current complicated relative path",
+			argFrom: "sub1",
+			argTo:   "sub2\\..",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.Cd("sub2")
+				fs.AddSubdir("sub1")
+				fs.Cd("\\")
+				return fs
+			},
+			expectedOutput: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := tt.fs()
+			output, err := execute("mv "+tt.argFrom+" "+tt.argTo, fs)
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestMvAndCopyToRootName(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		command      string
+		fs           func() *vfs.Filesystem
+		expectedTree []string
+	}{
+		{
+			name:    "mv to root name moves into root",
+			command: "mv      x       root",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\x")
+				fs.AddSubdir("sub1\\root")
+				fs.Cd("sub1")
+				return fs
+			},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"├── sub1",
+				"│   └── root",
+				"└── x",
+			},
+		},
+		{
+			name:    "mv renames to root with relative path",
+			command: "mv      x       .\\root",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("x")
+				return fs
+			},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"└── root",
+			},
+		},
+		{
+			name:    "mv moves into subdir named as root with relative path",
+			command: "mv      x       .\\root",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("x")
+				fs.AddSubdir(".\\root")
+				return fs
+			},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"└── root",
+				"    └── x",
+			},
+		},
+		{
+			name:    "copy to root name copies into root",
+			command: "copy    x       root",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\root")
+				fs.Touch("sub1\\x")
+				fs.Cd("sub1")
+				return fs
+			},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"├── x",
+				"└── sub1",
+				"    ├── x",
+				"    └── root",
+			},
+		},
+		{
+			name:    "copy into subdir named as root with relative path",
+			command: "copy    x       .\\root",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.Touch("x")
+				fs.AddSubdir(".\\root")
+				return fs
+			},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"├── x",
+				"└── root",
+				"    └── x",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := tt.fs()
+			if _, err := execute(tt.command, fs); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tree, _ := execute("tree    \\ /f", fs)
+			if diff := cmp.Diff(tt.expectedTree, tree); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleTouch(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name              string
+		cmdArg            string
+		fs                func() *vfs.Filesystem
+		expectedErr       error
+		expectedFileNames []string
+	}{
+		{
+			name:              "create new file",
+			cmdArg:            "file1.txt",
+			fs:                vfs.New,
+			expectedFileNames: []string{"file1.txt"},
+		},
+		{
+			name:   "touch existing file keeps content",
+			cmdArg: "file1.txt",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AppendFile("file1.txt", []byte("content\n"))
+				return fs
+			},
+			expectedFileNames: []string{"file1.txt"},
+		},
+		{
+			name:   "cannot create file with name of subdir",
+			cmdArg: "sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				return fs
+			},
+			expectedErr:       vfs.ErrSubdirAlreadyExists,
+			expectedFileNames: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := tt.fs()
+			output, err := execute("touch   "+tt.cmdArg, fs)
+			if output != nil {
+				t.Fatalf("unexpected output: %v", output)
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			fileNames := []string{}
+			for _, f := range fs.Current().Files() {
+				fileNames = append(fileNames, f.Name())
+			}
+			if diff := cmp.Diff(tt.expectedFileNames, fileNames); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		cmdArg         string
+		fs             func() *vfs.Filesystem
+		expectedOutput []string
+		expectedErr    error
+	}{
+		{
+			name:   "empty file",
+			cmdArg: "file1.txt",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.Touch("file1.txt")
+				return fs
+			},
+			expectedOutput: nil,
+		},
+		{
+			name:   "file with appended lines",
+			cmdArg: "file1.txt",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				execute("append  file1.txt first line", fs)
+				execute("append  file1.txt second   line", fs)
+				return fs
+			},
+			expectedOutput: []string{"first line", "second line"},
+		},
+		{
+			name:           "file does not exist",
+			cmdArg:         "file1.txt",
+			fs:             vfs.New,
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrFileDoesNotExist,
+		},
+		{
+			name:   "cannot type directory",
+			cmdArg: "sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrFileDoesNotExist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := execute("type    "+tt.cmdArg, tt.fs())
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestHandleRmdir(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		command          string
+		fs               func() *vfs.Filesystem
+		expectedErr      error
+		expectedSubNames []string
+	}{
+		{
+			name:    "remove empty subdir",
+			command: "rmdir   sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				return fs
+			},
+			expectedSubNames: []string{"sub2"},
+		},
+		{
+			name:    "cannot remove subdir with subdirs",
+			command: "rmdir   sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub2")
+				return fs
+			},
+			expectedErr:      vfs.ErrSubdirNotEmpty,
+			expectedSubNames: []string{"sub1"},
+		},
+		{
+			name:    "cannot remove subdir with files",
+			command: "rd      sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Touch("sub1\\file1.txt")
+				return fs
+			},
+			expectedErr:      vfs.ErrSubdirNotEmpty,
+			expectedSubNames: []string{"sub1"},
+		},
+		{
+			name:    "remove recursively",
+			command: "rd      /s      sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub2\\sub3")
+				fs.Touch("sub1\\file1.txt")
+				return fs
+			},
+			expectedSubNames: []string{},
+		},
+		{
+			name:    "deltree removes recursively",
+			command: "deltree sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub2")
+				fs.AddSubdir("sub3")
+				return fs
+			},
+			expectedSubNames: []string{"sub3"},
+		},
+		{
+			name:    "remove by relative path",
+			command: "rmdir   ..\\sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.Cd("sub1")
+				return fs
+			},
+			expectedSubNames: []string{"sub1"},
+		},
+		{
+			name:    "cannot remove current directory",
+			command: "rmdir   .",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Cd("sub1")
+				return fs
+			},
+			expectedErr:      vfs.ErrSubdirInUse,
+			expectedSubNames: []string{"sub1"},
+		},
+		{
+			name:    "cannot remove parent of current directory",
+			command: "deltree \\sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub2")
+				fs.Cd("sub1\\sub2")
+				return fs
+			},
+			expectedErr:      vfs.ErrSubdirInUse,
+			expectedSubNames: []string{"sub1"},
+		},
+		{
+			name:             "cannot remove root",
+			command:          "rd      /s      \\",
+			fs:               vfs.New,
+			expectedErr:      vfs.ErrSubdirInUse,
+			expectedSubNames: []string{},
+		},
+		{
+			name:             "subdir does not exist",
+			command:          "rmdir   sub1",
+			fs:               vfs.New,
+			expectedErr:      vfs.ErrSubdirDoesNotExist,
+			expectedSubNames: []string{},
+		},
+		{
+			name:             "invalid switch",
+			command:          "rmdir   /x      sub1",
+			fs:               vfs.New,
+			expectedErr:      ErrInvalidSwitch,
+			expectedSubNames: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := tt.fs()
+			output, err := execute(tt.command, fs)
+			if output != nil {
+				t.Fatalf("unexpected output: %v", output)
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			subDirNames := []string{}
+			for _, subdir := range fs.Root().Subdirs() {
+				subDirNames = append(subDirNames, subdir.Name())
+			}
+			if diff := cmp.Diff(tt.expectedSubNames, subDirNames); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleDel(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name              string
+		cmdArg            string
+		fs                func() *vfs.Filesystem
+		expectedErr       error
+		expectedFileNames []string
+	}{
+		{
+			name:   "remove file",
+			cmdArg: "file1.txt",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.Touch("file1.txt")
+				fs.Touch("file2.txt")
+				return fs
+			},
+			expectedFileNames: []string{"file2.txt"},
+		},
+		{
+			name:   "cannot remove directory",
+			cmdArg: "sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				return fs
+			},
+			expectedErr:       vfs.ErrFileDoesNotExist,
+			expectedFileNames: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := tt.fs()
+			_, err := execute("del     "+tt.cmdArg, fs)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			fileNames := []string{}
+			for _, f := range fs.Current().Files() {
+				fileNames = append(fileNames, f.Name())
+			}
+			if diff := cmp.Diff(tt.expectedFileNames, fileNames); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleCopy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		command      string
+		fs           func() *vfs.Filesystem
+		expectedErr  error
+		expectedTree []string
+	}{
+		{
+			name:    "copy directory with content under new name",
+			command: "copy    sub1    sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub3")
+				fs.Touch("sub1\\file1.txt")
+				return fs
+			},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"├── sub1",
+				"│   ├── file1.txt",
+				"│   └── sub3",
+				"└── sub2",
+				"    ├── file1.txt",
+				"    └── sub3",
+			},
+		},
+		{
+			name:    "copy into existing directory",
+			command: "xcopy   sub1    sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub3")
+				fs.AddSubdir("sub2")
+				return fs
+			},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"├── sub1",
+				"│   └── sub3",
+				"└── sub2",
+				"    └── sub1",
+				"        └── sub3",
+			},
+		},
+		{
+			name:    "copy file",
+			command: "cp      sub1\\file1.txt  file2.txt",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Touch("sub1\\file1.txt")
+				return fs
+			},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"├── file2.txt",
+				"└── sub1",
+				"    └── file1.txt",
+			},
+		},
+		{
+			name:    "destination already exists",
+			command: "copy    sub1    sub2",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2\\sub1\\sub3")
+				return fs
+			},
+			expectedErr: vfs.ErrSubdirAlreadyExists,
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"├── sub1",
+				"└── sub2",
+				"    └── sub1",
+				"        └── sub3",
+			},
+		},
+		{
+			name:    "overwrite existing destination",
+			command: "copy    sub1    sub2    /y",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub4")
+				fs.AddSubdir("sub2\\sub1\\sub3")
+				return fs
+			},
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"├── sub1",
+				"│   └── sub4",
+				"└── sub2",
+				"    └── sub1",
+				"        └── sub4",
+			},
+		},
+		{
+			name:    "cannot overwrite file with directory",
+			command: "copy    sub1    file1.txt /y",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Touch("file1.txt")
+				return fs
+			},
+			expectedErr: vfs.ErrFileAlreadyExists,
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"├── file1.txt",
+				"└── sub1",
+			},
+		},
+		{
+			name:    "cannot copy into itself",
+			command: "copy    sub1    sub1",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				return fs
+			},
+			expectedErr: vfs.ErrCopyIntoItself,
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"└── sub1",
+			},
+		},
+		{
+			name:    "cannot copy into own descendant",
+			command: "copy    sub1    sub1\\sub2\\sub3",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub2")
+				return fs
+			},
+			expectedErr: vfs.ErrCopyIntoItself,
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"└── sub1",
+				"    └── sub2",
+			},
+		},
+		{
+			name:    "cannot copy file onto itself",
+			command: "copy    file1.txt .       /y",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.Touch("file1.txt")
+				return fs
+			},
+			expectedErr: vfs.ErrCopyOntoItself,
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"└── file1.txt",
+			},
+		},
+		{
+			name:    "cannot copy directory onto itself",
+			command: "copy    sub1    .       /y",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub2")
+				return fs
+			},
+			expectedErr: vfs.ErrCopyOntoItself,
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"└── sub1",
+				"    └── sub2",
+			},
+		},
+		{
+			name:    "cannot copy file onto itself by name",
+			command: "copy    file1.txt file1.txt",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.Touch("file1.txt")
+				return fs
+			},
+			expectedErr: vfs.ErrCopyOntoItself,
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"└── file1.txt",
+			},
+		},
+		{
+			name:    "cannot overwrite parent of current directory",
+			command: "copy    \\sub1    \\sub2    /y",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2\\sub1\\sub3")
+				fs.Cd("sub2\\sub1\\sub3")
+				return fs
+			},
+			expectedErr: vfs.ErrSubdirInUse,
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+				"├── sub1",
+				"└── sub2",
+				"    └── sub1",
+				"        └── sub3",
+			},
+		},
+		{
+			name:        "source does not exist",
+			command:     "copy    sub1    sub2",
+			fs:          vfs.New,
+			expectedErr: vfs.ErrFileDoesNotExist,
+			expectedTree: []string{
+				"Tree of root:",
+				".",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := tt.fs()
+			_, err := execute(tt.command, fs)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			tree, _ := execute("tree    \\ /f", fs)
+			if diff := cmp.Diff(tt.expectedTree, tree); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleCopyIsDeep(t *testing.T) {
+	t.Parallel()
+	fs := vfs.New()
+	fs.AddSubdir("sub1")
+	fs.AppendFile("sub1\\file1.txt", []byte("first\n"))
+	if _, err := execute("copy    sub1    sub2", fs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fs.AppendFile("sub1\\file1.txt", []byte("second\n"))
+	fs.AddSubdir("sub1\\sub3")
+
+	output, _ := execute("type    sub2\\file1.txt", fs)
+	if diff := cmp.Diff([]string{"first"}, output); diff != "" {
+		t.Fatalf("output mismatch (-want +got):\n%s", diff)
+	}
+	if len(fs.Root().Subdirs()[1].Subdirs()) != 0 || fs.Root().Subdirs()[1].Parent() != fs.Root() {
+		t.Fatalf("copy is not independent of the source")
+	}
+}
+
+func TestHandleCommand(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		command        string
+		fs             func() *vfs.Filesystem
+		expectedOutput []string
+		expectedErr    error
+	}{
+		{
+			name:           "empty command",
+			fs:             vfs.New,
+			expectedOutput: nil,
+			expectedErr:    nil,
+		},
+		{
+			name:           "not known command",
+			command:        "notarealcommand",
+			fs:             vfs.New,
+			expectedOutput: nil,
+			expectedErr:    ErrUnknownCommand,
+		},
+		{
+			name:           "missing arguments",
+			command:        "mkdir    ",
+			fs:             vfs.New,
+			expectedOutput: nil,
+			expectedErr:    ErrWrongNumberOfArguments,
+		},
+		{
+			name:           "missing second argument",
+			command:        "mv      sub1",
+			fs:             vfs.New,
+			expectedOutput: nil,
+			expectedErr:    ErrWrongNumberOfArguments,
+		},
+		{
+			name:           "directory above root",
+			command:        "mkdir   ..",
+			fs:             vfs.New,
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrSubdirDoesNotExist,
+		},
+		{
+			name:    "empty path component",
+			command: "mv      sub1    sub2\\\\sub3",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				return fs
+			},
+			expectedOutput: nil,
+			expectedErr:    vfs.ErrInvalidPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := tt.fs()
+			output, err := execute(tt.command, fs)
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+		})
+	}
+
+}
+
+// executes the input on the filesystem using shell with built-in commands
+func execute(input string, fs *vfs.Filesystem) ([]string, error) {
+	return New(fs, Options{}).Execute(input)
+}
@@ -0,0 +1,206 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+// lists given directory, current directory by default
+// /b lists only names, /w lists subdirectories in brackets together with files,
+// /l lists each entry with its modification time and size
+// lines are wrapped to the width of the shell
+// wildcards in the last component of the path list only matching entries, e.g. dir sub?
+func (s *Shell) handleDir(fs *vfs.Filesystem, args []string) ([]string, error) {
+	opts := DirOptions{Width: s.opts.Width, ColumnWidth: s.opts.ColumnWidth}
+	layoutSet := false
+	path := ""
+	for _, arg := range args {
+		layout := opts.Layout
+		switch {
+		case strings.EqualFold(arg, "/b"):
+			layout = BareLayout
+		case strings.EqualFold(arg, "/w"):
+			layout = WideLayout
+		case strings.EqualFold(arg, "/l"):
+			layout = LongLayout
+		case strings.HasPrefix(arg, "/"):
+			return nil, ErrInvalidSwitch
+		case path != "":
+			return nil, ErrWrongNumberOfArguments
+		default:
+			path = arg
+			continue
+		}
+		if layoutSet && layout != opts.Layout {
+			return nil, fmt.Errorf("%w, only one of /b, /w and /l can be used", ErrInvalidSwitch)
+		}
+		opts.Layout = layout
+		layoutSet = true
+	}
+
+	target := fs.Current()
+	var err error
+	switch {
+	case vfs.HasWildcards(path):
+		target, opts.Pattern, err = fs.ResolvePattern(path)
+	case path != "":
+		target, err = fs.ResolveDir(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return RenderDir(target, opts), nil
+}
+
+// how dir lists entries of a directory
+type DirLayout int
+
+const (
+	// subdirectories and files in separate sections of aligned columns
+	ColumnsLayout DirLayout = iota
+	// names only, one per line and without header, subdirectories before files
+	BareLayout
+	// subdirectories in brackets followed by files, all in the same aligned columns
+	WideLayout
+	// one entry per line with its modification time, <DIR> or size and name, followed by totals
+	LongLayout
+)
+
+// options of the directory listing
+type DirOptions struct {
+	Layout DirLayout
+	// maximal length of lines with columns, 80 if not set
+	// a name longer than that gets a line of its own
+	Width int
+	// names in columns start at multiples of it, 8 if not set
+	ColumnWidth int
+	// only entries with names matching the pattern with wildcards are listed, all if not set
+	Pattern string
+}
+
+const (
+	defaultWidth       = 80
+	defaultColumnWidth = 8
+	// modification times are listed in local time of the filesystem
+	dirTimeLayout = "2006-01-02  15:04"
+)
+
+// lists entries of the directory, subdirectories are listed before files
+func RenderDir(d *vfs.Dir, opts DirOptions) []string {
+	if opts.Width <= 0 {
+		opts.Width = defaultWidth
+	}
+	if opts.ColumnWidth <= 0 {
+		opts.ColumnWidth = defaultColumnWidth
+	}
+	subdirs, files := d.Subdirs(), d.Files()
+	if opts.Pattern != "" {
+		subdirs, files = filterEntries(subdirs, opts.Pattern), filterEntries(files, opts.Pattern)
+	}
+
+	switch opts.Layout {
+	case BareLayout:
+		output := make([]string, 0, len(subdirs)+len(files))
+		for _, subdir := range subdirs {
+			output = append(output, subdir.Name())
+		}
+		for _, f := range files {
+			output = append(output, f.Name())
+		}
+		return output
+	case WideLayout:
+		names := make([]string, 0, len(subdirs)+len(files))
+		for _, subdir := range subdirs {
+			names = append(names, "["+subdir.Name()+"]")
+		}
+		for _, f := range files {
+			names = append(names, f.Name())
+		}
+		output := []string{"Directory of " + d.Path() + ":"}
+		return append(output, wrapColumns(names, opts.Width, opts.ColumnWidth)...)
+	case LongLayout:
+		return renderLongDir(d, subdirs, files)
+	}
+
+	output := []string{"Directory of " + d.Path() + ":"}
+	if len(subdirs) == 0 {
+		output = append(output, "No subdirectories")
+	}
+
+	subNames := []string{}
+	for _, subdir := range subdirs {
+		subNames = append(subNames, subdir.Name())
+	}
+	output = append(output, wrapColumns(subNames, opts.Width, opts.ColumnWidth)...)
+
+	if len(files) == 0 {
+		return output
+	}
+	fileNames := []string{}
+	for _, f := range files {
+		fileNames = append(fileNames, f.Name())
+	}
+	output = append(output, "Files:")
+	return append(output, wrapColumns(fileNames, opts.Width, opts.ColumnWidth)...)
+}
+
+func filterEntries[T interface{ Name() string }](entries []T, pattern string) []T {
+	matching := []T{}
+	for _, entry := range entries {
+		if vfs.MatchWildcard(pattern, entry.Name()) {
+			matching = append(matching, entry)
+		}
+	}
+	return matching
+}
+
+// lines in the style of DOS, e.g.
+// 2023-05-01  14:05    <DIR>          sub1
+// 2023-05-01  14:05                12 a.txt
+func renderLongDir(d *vfs.Dir, subdirs []*vfs.Dir, files []*vfs.File) []string {
+	output := []string{"Directory of " + d.Path() + ":"}
+	for _, subdir := range subdirs {
+		output = append(output, formatModTime(subdir.ModTime())+"    <DIR>          "+subdir.Name())
+	}
+	size := 0
+	for _, f := range files {
+		output = append(output, fmt.Sprintf("%s%18d %s", formatModTime(f.ModTime()), f.Size(), f.Name()))
+		size += f.Size()
+	}
+	return append(output,
+		fmt.Sprintf("%8d File(s) %14d bytes", len(files), size),
+		fmt.Sprintf("%8d Dir(s)", len(subdirs)),
+	)
+}
+
+// unknown time is left blank
+func formatModTime(t time.Time) string {
+	if t.IsZero() {
+		return strings.Repeat(" ", len(dirTimeLayout))
+	}
+	return t.Format(dirTimeLayout)
+}
+
+// wraps names into lines of given width, each name starts at a multiple of column width
+func wrapColumns(names []string, width, columnWidth int) []string {
+	if len(names) == 0 {
+		return nil
+	}
+	lines := []string{names[0]}
+	lineCounter := 0
+	for _, name := range names[1:] {
+		paddingLength := columnWidth - len(lines[lineCounter])%columnWidth
+		if len(lines[lineCounter])+len(name)+paddingLength > width {
+			lineCounter++
+			lines = append(lines, name)
+			continue
+		}
+		padding := paddingLength + len(lines[lineCounter])
+		lines[lineCounter] = fmt.Sprintf("%-*s", padding, lines[lineCounter])
+		lines[lineCounter] += name
+	}
+	return lines
+}
@@ -0,0 +1,139 @@
+package shell
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+func TestHandleDirLayouts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		input          string
+		opts           Options
+		expectedOutput []string
+		expectedErr    error
+	}{
+		{
+			name:  "default",
+			input: "dir",
+			expectedOutput: []string{
+				"Directory of root:",
+				"sub1    sub2",
+				"Files:",
+				"a.txt   b.txt",
+			},
+		},
+		{
+			name:  "bare",
+			input: "dir     /b",
+			expectedOutput: []string{
+				"sub1",
+				"sub2",
+				"a.txt",
+				"b.txt",
+			},
+		},
+		{
+			name:  "wide",
+			input: "dir     /W",
+			expectedOutput: []string{
+				"Directory of root:",
+				"[sub1]  [sub2]  a.txt   b.txt",
+			},
+		},
+		{
+			name:  "long",
+			input: "dir     /l",
+			expectedOutput: []string{
+				"Directory of root:",
+				"2023-05-01  14:05    <DIR>          sub1",
+				"2023-05-01  14:05    <DIR>          sub2",
+				"2023-05-01  14:05                11 a.txt",
+				"2023-05-02  15:05                 2 b.txt",
+				"       2 File(s)             13 bytes",
+				"       2 Dir(s)",
+			},
+		},
+		{
+			name:  "long with path",
+			input: "dir     sub1 /l",
+			expectedOutput: []string{
+				"Directory of root\\sub1:",
+				"       0 File(s)              0 bytes",
+				"       0 Dir(s)",
+			},
+		},
+		{
+			name:  "narrow terminal",
+			input: "dir     /w",
+			opts:  Options{Width: 14},
+			expectedOutput: []string{
+				"Directory of root:",
+				"[sub1]  [sub2]",
+				"a.txt   b.txt",
+			},
+		},
+		{
+			name:  "column width",
+			input: "dir",
+			opts:  Options{ColumnWidth: 6},
+			expectedOutput: []string{
+				"Directory of root:",
+				"sub1  sub2",
+				"Files:",
+				"a.txt b.txt",
+			},
+		},
+		{
+			name:  "repeated switch",
+			input: "dir     /b /B",
+			expectedOutput: []string{
+				"sub1",
+				"sub2",
+				"a.txt",
+				"b.txt",
+			},
+		},
+		{
+			name:        "conflicting switches",
+			input:       "dir     /b /l",
+			expectedErr: ErrInvalidSwitch,
+		},
+		{
+			name:        "unknown switch",
+			input:       "dir     /x",
+			expectedErr: ErrInvalidSwitch,
+		},
+		{
+			name:        "two paths",
+			input:       "dir     sub1 sub2",
+			expectedErr: ErrWrongNumberOfArguments,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := time.Date(2023, 5, 1, 14, 5, 0, 0, time.UTC)
+			fs := vfs.New()
+			fs.SetClock(func() time.Time { return created })
+			fs.AddSubdir("sub1")
+			fs.AddSubdir("sub2")
+			fs.AppendFile("a.txt", []byte("first line\n"))
+			fs.SetClock(func() time.Time { return created.Add(25 * time.Hour) })
+			fs.AppendFile("b.txt", []byte("a\n"))
+
+			output, err := New(fs, tt.opts).Execute(tt.input)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
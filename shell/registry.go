@@ -0,0 +1,77 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+var (
+	ErrCommandAlreadyRegistered = errors.New("Command already registered")
+)
+
+// Command can be executed by the shell once it's registered
+type Command interface {
+	// name used to invoke the command
+	Name() string
+	// alternative names used to invoke the command
+	Aliases() []string
+	// minimal and maximal number of arguments, negative maximum means no limit
+	Arity() (min, max int)
+	// short description of the command syntax, e.g. "mv <from> <to>"
+	Usage() string
+	// executes the command with arguments already checked against Arity
+	// returns output lines and error if the command failed
+	Execute(fs *vfs.Filesystem, args []string) ([]string, error)
+}
+
+// Command that writes its output while it runs instead of returning it at the end,
+// the shell uses ExecuteTo when writing output and Execute when output lines are returned
+type StreamingCommand interface {
+	Command
+	// same as Execute, but output lines are written to w, each ended with a newline
+	ExecuteTo(w io.Writer, fs *vfs.Filesystem, args []string) error
+}
+
+// maps names and aliases to the commands
+type registry struct {
+	commands map[string]Command
+}
+
+func newRegistry() *registry {
+	return &registry{
+		commands: map[string]Command{},
+	}
+}
+
+// built-in commands use settings of the shell
+func defaultRegistry(s *Shell) *registry {
+	r := newRegistry()
+	for _, cmd := range builtinCommands(s) {
+		if err := r.register(cmd); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// names of statements are taken as well
+func (r *registry) register(cmd Command) error {
+	names := append([]string{cmd.Name()}, cmd.Aliases()...)
+	for _, name := range names {
+		if _, ok := r.commands[name]; ok || isStatementName(name) {
+			return fmt.Errorf("%w: %s", ErrCommandAlreadyRegistered, name)
+		}
+	}
+	for _, name := range names {
+		r.commands[name] = cmd
+	}
+	return nil
+}
+
+func (r *registry) lookup(name string) (Command, bool) {
+	cmd, ok := r.commands[name]
+	return cmd, ok
+}
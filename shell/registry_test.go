@@ -0,0 +1,117 @@
+package shell
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+// custom command printing name of the current directory
+type pwdCommand struct{}
+
+func (pwdCommand) Name() string {
+	return "pwd"
+}
+
+func (pwdCommand) Aliases() []string {
+	return []string{"whereami"}
+}
+
+func (pwdCommand) Arity() (int, int) {
+	return 0, 0
+}
+
+func (pwdCommand) Usage() string {
+	return "pwd"
+}
+
+func (pwdCommand) Execute(fs *vfs.Filesystem, args []string) ([]string, error) {
+	return []string{fs.Current().Name()}, nil
+}
+
+func TestShellRegister(t *testing.T) {
+	t.Parallel()
+	fs := vfs.New()
+	fs.AddSubdir("sub1")
+	fs.Cd("sub1")
+	sh := New(fs, Options{})
+	if err := sh.Register(pwdCommand{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, input := range []string{"pwd", "whereami"} {
+		output, err := sh.Execute(input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff([]string{"sub1"}, output); diff != "" {
+			t.Fatalf("output mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	_, err := sh.Execute("pwd     sub1")
+	if !errors.Is(err, ErrWrongNumberOfArguments) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", ErrWrongNumberOfArguments, err)
+	}
+
+	// commands are registered per shell
+	_, err = execute("pwd", fs)
+	if !errors.Is(err, ErrUnknownCommand) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", ErrUnknownCommand, err)
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		cmd         Command
+		expectedErr error
+	}{
+		{
+			name: "new command",
+			cmd:  pwdCommand{},
+		},
+		{
+			name:        "name already taken",
+			cmd:         &command{name: "mkdir"},
+			expectedErr: ErrCommandAlreadyRegistered,
+		},
+		{
+			name:        "alias already taken",
+			cmd:         &command{name: "makedir", aliases: []string{"md"}},
+			expectedErr: ErrCommandAlreadyRegistered,
+		},
+		{
+			name:        "statement name reserved",
+			cmd:         &command{name: "if"},
+			expectedErr: ErrCommandAlreadyRegistered,
+		},
+		{
+			name: "statement name in other case",
+			cmd:  &command{name: "IF"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(vfs.New(), Options{}).commands
+			err := r.register(tt.cmd)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr != nil {
+				if cmd, ok := r.lookup(tt.cmd.Name()); ok && cmd == tt.cmd {
+					t.Fatalf("command registered despite conflict")
+				}
+				return
+			}
+			for _, name := range append([]string{tt.cmd.Name()}, tt.cmd.Aliases()...) {
+				if _, ok := r.lookup(name); !ok {
+					t.Fatalf("command not found by name %s", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNoSuchHistoryEntry = errors.New("No such command in history")
+)
+
+// reads line of input after showing the prompt, returns io.EOF when the input ends
+type lineReader func(prompt string, history []string) (string, error)
+
+// reads commands line by line and writes their output immediately
+// prompt shows path of the current directory, e.g. root\sub4>
+// besides registered commands supports:
+// history - lists previous commands, !! - repeats last command, !n - repeats n-th command,
+// exit - ends the session, as does end of the input
+func (s *Shell) Interactive(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	return s.interactive(w, func(prompt string, history []string) (string, error) {
+		io.WriteString(w, prompt)
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return scanner.Text(), nil
+	})
+}
+
+// same as Interactive, but lines are edited in the terminal: left and right arrows, home and end move the cursor,
+// up and down arrows walk the history, backspace and delete remove characters,
+// Ctrl+C drops the line and Ctrl+D on an empty line ends the session
+// makeRaw switches the terminal to raw mode while a line is read and returns function restoring it,
+// so that output of commands is written in the normal mode
+func (s *Shell) InteractiveTerminal(r io.Reader, w io.Writer, makeRaw func() (func(), error)) error {
+	e := &lineEditor{r: bufio.NewReader(r), w: w}
+	return s.interactive(w, func(prompt string, history []string) (string, error) {
+		restore, err := makeRaw()
+		if err != nil {
+			return "", err
+		}
+		defer restore()
+		return e.readLine(prompt, history)
+	})
+}
+
+func (s *Shell) interactive(w io.Writer, readLine lineReader) error {
+	history := []string{}
+	for {
+		line, err := readLine(s.fs.Current().Path()+">", history)
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(w)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		input := strings.TrimSpace(line)
+
+		if strings.HasPrefix(input, "!") {
+			recalled, err := recallHistory(history, input)
+			if err != nil {
+				fmt.Fprintln(w, err)
+				continue
+			}
+			input = recalled
+			fmt.Fprintln(w, input)
+		}
+		if input == "" {
+			continue
+		}
+		history = append(history, input)
+
+		switch name, _, _ := splitInput(input); name {
+		case "exit":
+			return nil
+		case "history":
+			for i, cmd := range history {
+				fmt.Fprintf(w, "%5d  %s\n", i+1, cmd)
+			}
+			continue
+		}
+
+		// output is flushed when the command ends, before the next prompt
+		writer := bufio.NewWriter(w)
+		if err := s.ExecuteTo(writer, input); err != nil {
+			fmt.Fprintln(writer, err)
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// returns command referenced by !! or !n, counting from 1
+func recallHistory(history []string, input string) (string, error) {
+	if input == "!!" {
+		if len(history) == 0 {
+			return "", ErrNoSuchHistoryEntry
+		}
+		return history[len(history)-1], nil
+	}
+	n, err := strconv.Atoi(input[1:])
+	if err != nil || n < 1 || n > len(history) {
+		return "", ErrNoSuchHistoryEntry
+	}
+	return history[n-1], nil
+}
+
+// keys of a terminal in raw mode
+const (
+	keyCtrlA     = 0x01
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyCtrlE     = 0x05
+	keyBackspace = 0x08
+	keyEnter     = '\r'
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+)
+
+// edits line in a terminal in raw mode, which neither echoes input nor moves to a new line on \n
+type lineEditor struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+func (e *lineEditor) readLine(prompt string, history []string) (string, error) {
+	line := []rune{}
+	cursor := 0
+	// position in the history, len(history) is the line being written, kept as draft while walking the history
+	position := len(history)
+	draft := line
+	recall := func(i int) {
+		if position == len(history) {
+			draft = line
+		}
+		position = i
+		if position == len(history) {
+			line = draft
+		} else {
+			line = []rune(history[position])
+		}
+		cursor = len(line)
+	}
+
+	e.redraw(prompt, line, cursor)
+	for {
+		key, _, err := e.r.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch key {
+		case keyEnter, '\n':
+			io.WriteString(e.w, "\r\n")
+			return string(line), nil
+		case keyCtrlC:
+			io.WriteString(e.w, "^C\r\n")
+			return "", nil
+		case keyCtrlD:
+			if len(line) == 0 {
+				return "", io.EOF
+			}
+		case keyBackspace, keyDelete:
+			if cursor > 0 {
+				line = append(line[:cursor-1:cursor-1], line[cursor:]...)
+				cursor--
+			}
+		case keyCtrlA:
+			cursor = 0
+		case keyCtrlE:
+			cursor = len(line)
+		case keyEscape:
+			switch e.readEscape() {
+			case "A":
+				if position > 0 {
+					recall(position - 1)
+				}
+			case "B":
+				if position < len(history) {
+					recall(position + 1)
+				}
+			case "C":
+				if cursor < len(line) {
+					cursor++
+				}
+			case "D":
+				if cursor > 0 {
+					cursor--
+				}
+			case "H", "1~":
+				cursor = 0
+			case "F", "4~":
+				cursor = len(line)
+			case "3~":
+				if cursor < len(line) {
+					line = append(line[:cursor:cursor], line[cursor+1:]...)
+				}
+			}
+		default:
+			if key < ' ' {
+				// other control characters are ignored
+				continue
+			}
+			line = append(line[:cursor:cursor], append([]rune{key}, line[cursor:]...)...)
+			cursor++
+		}
+		e.redraw(prompt, line, cursor)
+	}
+}
+
+// returns final part of the escape sequence of a key, e.g. A for up arrow sent as ESC [ A,
+// empty for unknown sequences
+func (e *lineEditor) readEscape() string {
+	if next, err := e.r.ReadByte(); err != nil || (next != '[' && next != 'O') {
+		return ""
+	}
+	var sequence strings.Builder
+	for {
+		c, err := e.r.ReadByte()
+		if err != nil {
+			return ""
+		}
+		sequence.WriteByte(c)
+		// parameters are digits and ;, the sequence ends with any other character
+		if (c < '0' || c > '9') && c != ';' {
+			return sequence.String()
+		}
+	}
+}
+
+// writes the whole line again and moves the cursor back to its position
+func (e *lineEditor) redraw(prompt string, line []rune, cursor int) {
+	output := "\r" + prompt + string(line) + "\x1b[K"
+	if back := len(line) - cursor; back > 0 {
+		output += fmt.Sprintf("\x1b[%dD", back)
+	}
+	io.WriteString(e.w, output)
+}
@@ -0,0 +1,170 @@
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+func TestRunInteractive(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		input          []string
+		expectedOutput []string
+	}{
+		{
+			name:  "prompt shows current directory",
+			input: []string{"mkdir sub4", "cd sub4", "dir", "up"},
+			expectedOutput: []string{
+				"root>root>root\\sub4>Directory of root\\sub4:",
+				"No subdirectories",
+				"root\\sub4>root>",
+			},
+		},
+		{
+			name:  "errors do not end the session",
+			input: []string{"cd sub1", "notacommand", "up"},
+			expectedOutput: []string{
+				"root>Subdirectory does not exist",
+				"root>Command not known",
+				"root>Cannot move up from root directory",
+				"root>",
+			},
+		},
+		{
+			name:  "history is listed and recalled",
+			input: []string{"mkdir sub1", "", "dir", "history", "!1", "!!", "!9"},
+			expectedOutput: []string{
+				"root>root>root>Directory of root:",
+				"sub1",
+				"root>    1  mkdir sub1",
+				"    2  dir",
+				"    3  history",
+				"root>mkdir sub1",
+				"Subdirectory already exists",
+				"root>mkdir sub1",
+				"Subdirectory already exists",
+				"root>No such command in history",
+				"root>",
+			},
+		},
+		{
+			name:  "exit ends the session",
+			input: []string{"mkdir sub1", "exit", "mkdir sub2"},
+			expectedOutput: []string{
+				"root>root>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := vfs.New()
+			var output bytes.Buffer
+			input := strings.NewReader(strings.Join(tt.input, "\n") + "\n")
+			if err := New(fs, Options{}).Interactive(input, &output); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+			if diff := cmp.Diff(tt.expectedOutput, lines); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunInteractiveTerminal(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		keys           string
+		expectedDirs   []string
+		expectedOutput string
+	}{
+		{
+			name:         "line is redrawn after each key",
+			keys:         "dir\r",
+			expectedDirs: []string{},
+			expectedOutput: "\rroot>\x1b[K\rroot>d\x1b[K\rroot>di\x1b[K\rroot>dir\x1b[K\r\n" +
+				"Directory of root:\n" +
+				"No subdirectories\n" +
+				"\rroot>\x1b[K\n",
+		},
+		{
+			name:         "typed lines",
+			keys:         "mkdir sub1\rmkdir sub2\n",
+			expectedDirs: []string{"sub1", "sub2"},
+		},
+		{
+			name:         "up arrow recalls previous line for editing",
+			keys:         "mkdir sub1\r\x1b[A\x7f2\r",
+			expectedDirs: []string{"sub1", "sub2"},
+		},
+		{
+			name:         "down arrow returns to the draft",
+			keys:         "mkdir sub1\rmkdir sub2\rmkdir\x1b[A\x1b[A\x1b[B\x1b[B sub3\r",
+			expectedDirs: []string{"sub1", "sub2", "sub3"},
+		},
+		{
+			name:         "cursor moves within the line",
+			keys:         "mkdir sb3\x1b[D\x1b[Du\x01\x1b[3~m\x05\x08\x1b[D\x1b[C4\r",
+			expectedDirs: []string{"sub4"},
+		},
+		{
+			name:         "home and end keys",
+			keys:         "kdir sub1\x1b[Hm\x1b[F2\r",
+			expectedDirs: []string{"sub12"},
+		},
+		{
+			name:         "cursor moves by characters",
+			keys:         "mkdir süb\x1b[D\x7fu\r",
+			expectedDirs: []string{"sub"},
+		},
+		{
+			name:         "ctrl+c drops the line",
+			keys:         "mkdir sub1\x03mkdir sub2\r",
+			expectedDirs: []string{"sub2"},
+		},
+		{
+			name:         "ctrl+d on empty line ends the session",
+			keys:         "mkdir sub1\r\x04mkdir sub2\r",
+			expectedDirs: []string{"sub1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := vfs.New()
+			var output bytes.Buffer
+			raw := 0
+			makeRaw := func() (func(), error) {
+				raw++
+				return func() { raw-- }, nil
+			}
+			err := New(fs, Options{}).InteractiveTerminal(strings.NewReader(tt.keys), &output, makeRaw)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if raw != 0 {
+				t.Fatalf("terminal left in raw mode")
+			}
+			dirs := []string{}
+			for _, subdir := range fs.Root().Subdirs() {
+				dirs = append(dirs, subdir.Name())
+			}
+			if diff := cmp.Diff(tt.expectedDirs, dirs); diff != "" {
+				t.Fatalf("directories mismatch (-want +got):\n%s", diff)
+			}
+			if tt.expectedOutput == "" {
+				return
+			}
+			if diff := cmp.Diff(tt.expectedOutput, output.String()); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,443 @@
+package shell
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidSyntax     = errors.New("Invalid syntax")
+	ErrCallNotAvailable  = errors.New("Scripts cannot be called in this shell")
+	ErrCallDepthExceeded = errors.New("Too many nested calls")
+	ErrZeroLoopStep      = errors.New("Loop step cannot be zero")
+)
+
+const (
+	ifUsage   = "if [not] exist <path> <command> | (<commands>) [else (<commands>)]"
+	forUsage  = "for [/l] %i in (<items> | <start>,<step>,<end>) do <command> | (<commands>)"
+	callUsage = "call <script> [arguments]"
+
+	// limits recursion of scripts calling themselves
+	maxCallDepth = 16
+)
+
+// executes lines of a script and commands nested in statements
+// statements if, for and call are parsed by the runner, other commands are executed by the shell
+type runner struct {
+	s *Shell
+	w *outputWriter
+	// errors of commands are written to w and the policy decides whether the runner continues,
+	// otherwise the first error ends the runner and is returned without being written
+	report bool
+	policy ErrorPolicy
+	// arguments of the called script, the first one is its name, nil outside of called scripts
+	args  []string
+	depth int
+}
+
+// writer of the runner, it remembers the first error and writes nothing after it
+// the error stops the runner whatever the policy is, as nothing more can be reported
+type outputWriter struct {
+	w   io.Writer
+	err error
+}
+
+func newOutputWriter(w io.Writer) *outputWriter {
+	return &outputWriter{w: w}
+}
+
+func (o *outputWriter) Write(p []byte) (int, error) {
+	if o.err != nil {
+		return 0, o.err
+	}
+	n, err := o.w.Write(p)
+	o.err = err
+	return n, err
+}
+
+// error already written by the runner which stops the script
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *reportedError) Unwrap() error {
+	return e.err
+}
+
+// executes line of a script, it is prepared and echoed first
+// returns error if the script should stop
+func (r *runner) runLine(line string) error {
+	if isComment(line) && !r.s.opts.EchoComments {
+		return nil
+	}
+	input, err := r.s.prepare(line, r.args)
+	io.WriteString(r.w, CommandEcho(input)+"\n")
+	if err == nil {
+		err = r.execute(input)
+	}
+	return r.handle(err)
+}
+
+// executes command nested in a statement, it is echoed first, but not expanded again
+// returns error if the script should stop
+func (r *runner) runCommand(input string) error {
+	io.WriteString(r.w, CommandEcho(input)+"\n")
+	return r.handle(r.execute(input))
+}
+
+func (r *runner) runCommands(commands []string) error {
+	for _, cmd := range commands {
+		if err := r.runCommand(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writes the error if the runner reports errors and returns it if the script should stop
+// error of the output is always returned
+func (r *runner) handle(err error) error {
+	if r.w.err != nil {
+		return r.w.err
+	}
+	var reported *reportedError
+	if err == nil || !r.report || errors.As(err, &reported) {
+		return err
+	}
+	if _, writeErr := io.WriteString(r.w, err.Error()+"\n"); writeErr != nil {
+		return writeErr
+	}
+	if r.policy == ContinueOnError {
+		return nil
+	}
+	return &reportedError{err: err}
+}
+
+// executes statement or command of the input, without echo
+func (r *runner) execute(input string) error {
+	tokens, err := tokenize(input)
+	if err != nil || len(tokens) == 0 {
+		return r.s.executeTo(r.w, input)
+	}
+	switch tokens[0].value {
+	case "if":
+		return r.executeIf(input, tokens)
+	case "for":
+		return r.executeFor(input, tokens)
+	case "call":
+		return r.executeCall(tokens)
+	}
+	return r.s.executeTo(r.w, input)
+}
+
+func isStatement(input string) bool {
+	name, _, _ := splitInput(input)
+	return isStatementName(name)
+}
+
+// names of statements are reserved, commands cannot be registered under them
+// they are case-sensitive as names of commands are
+func isStatementName(name string) bool {
+	switch name {
+	case "if", "for", "call":
+		return true
+	}
+	return false
+}
+
+// if [not] exist path command runs the command if a directory or file exists,
+// parenthesized commands are separated by &, e.g. if exist sub1 (cd sub1 & mkdir sub2) else (mkdir sub1)
+func (r *runner) executeIf(input string, tokens []token) error {
+	i := 1
+	negated := i < len(tokens) && strings.EqualFold(tokens[i].value, "not")
+	if negated {
+		i++
+	}
+	if i+1 >= len(tokens) || !strings.EqualFold(tokens[i].value, "exist") {
+		return fmt.Errorf("%w, usage: %s", ErrInvalidSyntax, ifUsage)
+	}
+	path := tokens[i+1]
+
+	then, rest, err := cutBody(input[path.start+len(path.raw):])
+	if err != nil {
+		return fmt.Errorf("%w, usage: %s", err, ifUsage)
+	}
+	var otherwise []string
+	if rest != "" {
+		elseBody, ok := cutKeyword(rest, "else")
+		if !ok {
+			return fmt.Errorf("%w, usage: %s", ErrInvalidSyntax, ifUsage)
+		}
+		otherwise, rest, err = cutBody(elseBody)
+		if err != nil || rest != "" {
+			return fmt.Errorf("%w, usage: %s", ErrInvalidSyntax, ifUsage)
+		}
+	}
+
+	if r.exists(path.value) != negated {
+		return r.runCommands(then)
+	}
+	return r.runCommands(otherwise)
+}
+
+func (r *runner) exists(path string) bool {
+	if _, err := r.s.fs.ResolveDir(path); err == nil {
+		return true
+	}
+	_, err := r.s.fs.ResolveFile(path)
+	return err == nil
+}
+
+// for %i in (a b c) do command runs the command for each item with %i replaced by it,
+// for /l %i in (start,step,end) does so for numbers from start to end inclusive
+// items are separated by spaces or commas and keep their quotes, e.g. "My Docs"
+func (r *runner) executeFor(input string, tokens []token) error {
+	i := 1
+	numeric := i < len(tokens) && strings.EqualFold(tokens[i].value, "/l")
+	if numeric {
+		i++
+	}
+	if i+1 >= len(tokens) || !isLoopVariable(tokens[i].value) || !strings.EqualFold(tokens[i+1].value, "in") {
+		return fmt.Errorf("%w, usage: %s", ErrInvalidSyntax, forUsage)
+	}
+	variable := tokens[i].value
+	in := tokens[i+1]
+
+	set, rest, err := cutGroup(strings.TrimLeft(input[in.start+len(in.raw):], " \t"))
+	if err != nil {
+		return fmt.Errorf("%w, usage: %s", err, forUsage)
+	}
+	body, ok := cutKeyword(rest, "do")
+	if !ok {
+		return fmt.Errorf("%w, usage: %s", ErrInvalidSyntax, forUsage)
+	}
+	commands, rest, err := cutBody(body)
+	if err != nil || rest != "" {
+		return fmt.Errorf("%w, usage: %s", ErrInvalidSyntax, forUsage)
+	}
+
+	run := func(item string) error {
+		for _, cmd := range commands {
+			if err := r.runCommand(strings.ReplaceAll(cmd, variable, item)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !numeric {
+		items, err := splitItems(set)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := run(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// numbers are generated one at a time, so that a huge range doesn't take memory before the first command runs
+	// there are no numbers if the step goes away from the end
+	start, step, end, err := parseRange(set)
+	if err != nil {
+		return err
+	}
+	for n := start; (step > 0 && n <= end) || (step < 0 && n >= end); n += step {
+		if err := run(strconv.Itoa(n)); err != nil {
+			return err
+		}
+		// the next number would overflow
+		if (step > 0 && n > math.MaxInt-step) || (step < 0 && n < math.MinInt-step) {
+			break
+		}
+	}
+	return nil
+}
+
+// loop variable is % followed by a single letter, e.g. %i, in scripts it is written as %%i
+func isLoopVariable(name string) bool {
+	if len(name) != 2 || name[0] != '%' {
+		return false
+	}
+	c := name[1] | 0x20
+	return c >= 'a' && c <= 'z'
+}
+
+func splitItems(set string) ([]string, error) {
+	// commas outside of quotes separate items as spaces do
+	separated := []byte(set)
+	quoted := false
+	for i := 0; i < len(separated); i++ {
+		switch c := separated[i]; {
+		case c == '^':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			separated[i] = ' '
+		}
+	}
+	tokens, err := tokenize(string(separated))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		items = append(items, t.raw)
+	}
+	return items, nil
+}
+
+// returns start, step and end of the set written as start,step,end
+func parseRange(set string) (int, int, int, error) {
+	fields := strings.FieldsFunc(set, func(c rune) bool {
+		return c == ',' || c == ' ' || c == '\t'
+	})
+	if len(fields) != 3 {
+		return 0, 0, 0, fmt.Errorf("%w, usage: %s", ErrInvalidSyntax, forUsage)
+	}
+	var numbers [3]int
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("%w, usage: %s", ErrInvalidSyntax, forUsage)
+		}
+		numbers[i] = n
+	}
+	if numbers[1] == 0 {
+		return 0, 0, 0, ErrZeroLoopStep
+	}
+	return numbers[0], numbers[1], numbers[2], nil
+}
+
+// call script arguments runs lines of the script from the scripts of the shell,
+// %1 to %9 are replaced with its arguments, %0 with its name and %* with all arguments
+func (r *runner) executeCall(tokens []token) error {
+	if len(tokens) < 2 {
+		return fmt.Errorf("%w, usage: %s", ErrInvalidSyntax, callUsage)
+	}
+	if r.s.opts.Scripts == nil {
+		return ErrCallNotAvailable
+	}
+	if r.depth >= maxCallDepth {
+		return ErrCallDepthExceeded
+	}
+	content, err := iofs.ReadFile(r.s.opts.Scripts, strings.ReplaceAll(tokens[1].value, "\\", "/"))
+	if err != nil {
+		return err
+	}
+
+	called := *r
+	called.args = []string{}
+	for _, t := range tokens[1:] {
+		called.args = append(called.args, t.raw)
+	}
+	called.depth++
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if err := called.runLine(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// returns commands of the body of a statement and the rest of the input after it
+// body is a single command taking the rest of the input or commands in parentheses separated by &
+func cutBody(input string) ([]string, string, error) {
+	input = strings.TrimLeft(input, " \t")
+	if !strings.HasPrefix(input, "(") {
+		if input == "" {
+			return nil, "", ErrInvalidSyntax
+		}
+		return []string{input}, "", nil
+	}
+	group, rest, err := cutGroup(input)
+	if err != nil {
+		return nil, "", err
+	}
+	commands := []string{}
+	for _, cmd := range splitOutside(group, '&') {
+		if cmd = strings.TrimSpace(cmd); cmd != "" {
+			commands = append(commands, cmd)
+		}
+	}
+	return commands, strings.TrimSpace(rest), nil
+}
+
+// returns content of the parentheses the input starts with and the rest after them
+// parentheses in quotes and escaped with ^ are not counted
+func cutGroup(input string) (string, string, error) {
+	if !strings.HasPrefix(input, "(") {
+		return "", "", ErrInvalidSyntax
+	}
+	depth := 0
+	quoted := false
+	for i := 0; i < len(input); i++ {
+		switch c := input[i]; {
+		case c == '^':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return input[1:i], input[i+1:], nil
+			}
+		}
+	}
+	return "", "", ErrInvalidSyntax
+}
+
+// splits the input by the separator outside of quotes and parentheses and not escaped with ^
+func splitOutside(input string, separator byte) []string {
+	parts := []string{}
+	depth := 0
+	quoted := false
+	start := 0
+	for i := 0; i < len(input); i++ {
+		switch c := input[i]; {
+		case c == '^':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == separator && depth == 0:
+			parts = append(parts, input[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, input[start:])
+}
+
+// returns the rest of the input after the keyword, which must be followed by whitespace or (
+func cutKeyword(input, keyword string) (string, bool) {
+	input = strings.TrimLeft(input, " \t")
+	if len(input) <= len(keyword) || !strings.EqualFold(input[:len(keyword)], keyword) {
+		return "", false
+	}
+	rest := input[len(keyword):]
+	if c := rest[0]; c != ' ' && c != '\t' && c != '(' {
+		return "", false
+	}
+	return rest, true
+}
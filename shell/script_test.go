@@ -0,0 +1,244 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+func TestShellRunStatements(t *testing.T) {
+	t.Parallel()
+	scripts := fstest.MapFS{
+		"module.txt": {Data: []byte(
+			"rem     %1 is the name of the module\n" +
+				"mkdir   %1\\src\n" +
+				"append  %1\\info.txt %0 called with %*, missing: [%3]\n",
+		)},
+		"lib/nested.txt": {Data: []byte("call    lib\\nested.txt\n")},
+	}
+	tests := []struct {
+		name            string
+		script          string
+		policy          ErrorPolicy
+		expectedOutput  string
+		expectedErrLine int
+	}{
+		{
+			name: "if exist",
+			script: "mkdir   sub1\n" +
+				"if      exist   sub1 (mkdir sub2) else (mkdir sub3)\n" +
+				"if      exist   sub4 (mkdir sub5 & mkdir sub6) else (mkdir sub7 & mkdir sub8)\n" +
+				"if      not exist sub9 mkdir sub9\n" +
+				"if      exist   sub4 mkdir sub10\n" +
+				"dir\n",
+			expectedOutput: "Command: mkdir   sub1\n" +
+				"Command: if      exist   sub1 (mkdir sub2) else (mkdir sub3)\n" +
+				"Command: mkdir   sub2\n" +
+				"Command: if      exist   sub4 (mkdir sub5 & mkdir sub6) else (mkdir sub7 & mkdir sub8)\n" +
+				"Command: mkdir   sub7\n" +
+				"Command: mkdir   sub8\n" +
+				"Command: if      not     exist sub9 mkdir sub9\n" +
+				"Command: mkdir   sub9\n" +
+				"Command: if      exist   sub4 mkdir sub10\n" +
+				"Command: dir\n" +
+				"Directory of root:\n" +
+				"sub1    sub2    sub7    sub8    sub9\n",
+		},
+		{
+			name: "if exist file",
+			script: "touch   a.txt\n" +
+				"if      exist   a.txt type a.txt\n",
+			expectedOutput: "Command: touch   a.txt\n" +
+				"Command: if      exist   a.txt type a.txt\n" +
+				"Command: type    a.txt\n",
+		},
+		{
+			name: "for items",
+			script: "for     %%i     in (sub1 \"My Docs\",sub2) do mkdir %%i\n" +
+				"dir     /b\n",
+			expectedOutput: "Command: for     %i      in (sub1 \"My Docs\",sub2) do mkdir %i\n" +
+				"Command: mkdir   sub1\n" +
+				"Command: mkdir   \"My Docs\"\n" +
+				"Command: mkdir   sub2\n" +
+				"Command: dir     /b\n" +
+				"My Docs\n" +
+				"sub1\n" +
+				"sub2\n",
+		},
+		{
+			name: "numeric for with nested statements",
+			script: "mkdir   sub3\n" +
+				"for     /l      %%n in (5,-2,1) do (if not exist sub%%n mkdir sub%%n & append log.txt %%n)\n" +
+				"type    log.txt\n",
+			expectedOutput: "Command: mkdir   sub3\n" +
+				"Command: for     /l      %n in (5,-2,1) do (if not exist sub%n mkdir sub%n & append log.txt %n)\n" +
+				"Command: if      not     exist sub5 mkdir sub5\n" +
+				"Command: mkdir   sub5\n" +
+				"Command: append  log.txt 5\n" +
+				"Command: if      not     exist sub3 mkdir sub3\n" +
+				"Command: append  log.txt 3\n" +
+				"Command: if      not     exist sub1 mkdir sub1\n" +
+				"Command: mkdir   sub1\n" +
+				"Command: append  log.txt 1\n" +
+				"Command: type    log.txt\n" +
+				"5\n" +
+				"3\n" +
+				"1\n",
+		},
+		{
+			name: "call with arguments",
+			script: "set     NAME=core\n" +
+				"call    module.txt %NAME% second\n" +
+				"type    core\\info.txt\n",
+			expectedOutput: "Command: set     NAME=core\n" +
+				"Command: call    module.txt core second\n" +
+				"Command: mkdir   core\\src\n" +
+				"Command: append  core\\info.txt module.txt called with core second, missing: []\n" +
+				"Command: type    core\\info.txt\n" +
+				"module.txt called with core second, missing: []\n",
+		},
+		{
+			name: "failing command in loop continues",
+			script: "mkdir   sub2\n" +
+				"for     /l      %%i in (1,1,3) do mkdir sub%%i\n" +
+				"dir\n",
+			expectedOutput: "Command: mkdir   sub2\n" +
+				"Command: for     /l      %i in (1,1,3) do mkdir sub%i\n" +
+				"Command: mkdir   sub1\n" +
+				"Command: mkdir   sub2\n" +
+				"Subdirectory already exists\n" +
+				"Command: mkdir   sub3\n" +
+				"Command: dir\n" +
+				"Directory of root:\n" +
+				"sub1    sub2    sub3\n",
+		},
+		{
+			name:   "failing command in loop stops",
+			policy: StrictOnError,
+			script: "mkdir   sub2\n" +
+				"for     /l      %%i in (1,1,3) do mkdir sub%%i\n" +
+				"dir\n",
+			expectedOutput: "Command: mkdir   sub2\n" +
+				"Command: for     /l      %i in (1,1,3) do mkdir sub%i\n" +
+				"Command: mkdir   sub1\n" +
+				"Command: mkdir   sub2\n" +
+				"Subdirectory already exists\n",
+			expectedErrLine: 2,
+		},
+		{
+			name:   "huge range stops at the first failing command",
+			policy: StopOnError,
+			script: "for     /l      %%i in (1,1,1000000000) do up\n" +
+				"dir\n",
+			expectedOutput: "Command: for     /l      %i in (1,1,1000000000) do up\n" +
+				"Command: up\n" +
+				"Cannot move up from root directory\n",
+		},
+		{
+			name: "range ending at the largest number",
+			script: fmt.Sprintf("for     /l      %%%%i in (%d,1,%d) do append log.txt %%%%i\n", math.MaxInt-1, math.MaxInt) +
+				"type    log.txt\n",
+			expectedOutput: fmt.Sprintf("Command: for     /l      %%i in (%d,1,%d) do append log.txt %%i\n", math.MaxInt-1, math.MaxInt) +
+				fmt.Sprintf("Command: append  log.txt %d\n", math.MaxInt-1) +
+				fmt.Sprintf("Command: append  log.txt %d\n", math.MaxInt) +
+				"Command: type    log.txt\n" +
+				fmt.Sprintf("%d\n%d\n", math.MaxInt-1, math.MaxInt),
+		},
+		{
+			name:   "failing line of called script stops",
+			policy: StopOnError,
+			script: "mkdir   core\\src\n" +
+				"call    module.txt core\n" +
+				"dir\n",
+			expectedOutput: "Command: mkdir   core\\src\n" +
+				"Command: call    module.txt core\n" +
+				"Command: mkdir   core\\src\n" +
+				"Subdirectory already exists\n",
+		},
+		{
+			name:   "recursive call",
+			policy: StrictOnError,
+			script: "call    lib\\nested.txt\n",
+			expectedOutput: strings.Repeat("Command: call    lib\\nested.txt\n", maxCallDepth+1) +
+				"Too many nested calls\n",
+			expectedErrLine: 1,
+		},
+		{
+			name: "syntax errors",
+			script: "if      sub1 mkdir sub2\n" +
+				"if      exist   sub1 (mkdir sub2\n" +
+				"if      exist   sub1 (mkdir sub2) mkdir sub3\n" +
+				"for     %%i     in (a b) mkdir %%i\n" +
+				"for     i       in (a b) do mkdir %%i\n" +
+				"for     /l      %%i in (1,0,3) do mkdir sub%%i\n" +
+				"for     /l      %%i in (1,a,3) do mkdir sub%%i\n" +
+				"call\n",
+			expectedOutput: "Command: if      sub1    mkdir sub2\n" +
+				"Invalid syntax, usage: " + ifUsage + "\n" +
+				"Command: if      exist   sub1 (mkdir sub2\n" +
+				"Invalid syntax, usage: " + ifUsage + "\n" +
+				"Command: if      exist   sub1 (mkdir sub2) mkdir sub3\n" +
+				"Invalid syntax, usage: " + ifUsage + "\n" +
+				"Command: for     %i      in (a b) mkdir %i\n" +
+				"Invalid syntax, usage: " + forUsage + "\n" +
+				"Command: for     i       in (a b) do mkdir %i\n" +
+				"Invalid syntax, usage: " + forUsage + "\n" +
+				"Command: for     /l      %i in (1,0,3) do mkdir sub%i\n" +
+				"Loop step cannot be zero\n" +
+				"Command: for     /l      %i in (1,a,3) do mkdir sub%i\n" +
+				"Invalid syntax, usage: " + forUsage + "\n" +
+				"Command: call\n" +
+				"Invalid syntax, usage: " + callUsage + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			sh := New(vfs.New(), Options{Scripts: scripts})
+			err := sh.Run(strings.NewReader(tt.script), &output, tt.policy)
+			var lineErr *LineError
+			if tt.expectedErrLine == 0 && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectedErrLine != 0 && (!errors.As(err, &lineErr) || lineErr.Line != tt.expectedErrLine) {
+				t.Fatalf("expected error in line %d, got: %v", tt.expectedErrLine, err)
+			}
+			if diff := cmp.Diff(tt.expectedOutput, output.String()); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestShellExecuteStatement(t *testing.T) {
+	t.Parallel()
+	sh := New(vfs.New(), Options{})
+	output, err := sh.Execute("for %i in (sub1 sub1 sub2) do mkdir %i")
+	if !errors.Is(err, vfs.ErrSubdirAlreadyExists) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", vfs.ErrSubdirAlreadyExists, err)
+	}
+	expectedOutput := []string{
+		"Command: mkdir   sub1",
+		"Command: mkdir   sub1",
+	}
+	if diff := cmp.Diff(expectedOutput, output); diff != "" {
+		t.Fatalf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := sh.Execute("call other.txt"); !errors.Is(err, ErrCallNotAvailable) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", ErrCallNotAvailable, err)
+	}
+
+	// statement names are case-sensitive as command names are
+	if _, err := sh.Execute("IF exist sub1 mkdir sub2"); !errors.Is(err, ErrUnknownCommand) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", ErrUnknownCommand, err)
+	}
+}
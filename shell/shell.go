@@ -0,0 +1,277 @@
+// Package shell executes DOS-like commands, such as mkdir, cd or tree,
+// on a simulated filesystem from package vfs.
+package shell
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"strings"
+
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+var (
+	ErrUnknownErrorPolicy = errors.New("unknown error policy")
+)
+
+// decides what happens with the run when a command fails
+type ErrorPolicy int
+
+const (
+	// error is written to the output and processing continues
+	ContinueOnError ErrorPolicy = iota
+	// error is written to the output and processing stops
+	StopOnError
+	// same as StopOnError, but the run is reported as failed
+	StrictOnError
+)
+
+// returns policy named continue, stop or strict
+func ParseErrorPolicy(policy string) (ErrorPolicy, error) {
+	switch policy {
+	case "continue":
+		return ContinueOnError, nil
+	case "stop":
+		return StopOnError, nil
+	case "strict":
+		return StrictOnError, nil
+	}
+	return ContinueOnError, ErrUnknownErrorPolicy
+}
+
+// error of the command in given line of the input
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// settings of the shell used by all its commands
+type Options struct {
+	// draw trees with ASCII characters instead of box-drawing ones, as tree /a does
+	ASCIITree bool
+	// width of the terminal, lines of dir listings are wrapped to it, 80 if not set
+	Width int
+	// names in dir listings start at multiples of it, 8 if not set
+	ColumnWidth int
+	// reject input lines which are not in the fixed-column format with ErrMisalignedColumns,
+	// e.g. "cd sub1" instead of "cd      sub1", otherwise arguments may be separated by any whitespace
+	// comments are not checked
+	StrictColumns bool
+	// initial variables of the shell, e.g. from the environment of the process
+	Variables map[string]string
+	// write echo of comment lines in Run, otherwise they are skipped silently
+	EchoComments bool
+	// scripts run by the call command, names are relative to it, e.g. os.DirFS of the directory of the input
+	// call fails with ErrCallNotAvailable if it is nil
+	Scripts iofs.FS
+}
+
+// executes commands on the filesystem
+// each shell has its own set of commands, built-in commands are always available
+type Shell struct {
+	fs       *vfs.Filesystem
+	opts     Options
+	commands *registry
+	// variables set with the set command, by upper-case name
+	vars map[string]variable
+}
+
+// creates shell working on given filesystem with built-in commands
+func New(fs *vfs.Filesystem, opts Options) *Shell {
+	s := &Shell{
+		fs:   fs,
+		opts: opts,
+		vars: map[string]variable{},
+	}
+	for name, value := range opts.Variables {
+		s.vars[strings.ToUpper(name)] = variable{name: name, value: value}
+	}
+	s.commands = defaultRegistry(s)
+	return s
+}
+
+func (s *Shell) Filesystem() *vfs.Filesystem {
+	return s.fs
+}
+
+// makes the command available under its name and aliases
+// returns error if any of them is already taken
+func (s *Shell) Register(cmd Command) error {
+	return s.commands.register(cmd)
+}
+
+// executes single input line on the filesystem using registered commands
+// variables are expanded first, comment lines do nothing
+// returns output lines of the command and error if the command failed
+func (s *Shell) Execute(input string) ([]string, error) {
+	input, err := s.prepare(input, nil)
+	if err != nil {
+		return nil, err
+	}
+	if isStatement(input) {
+		var buf bytes.Buffer
+		err := (&runner{s: s, w: newOutputWriter(&buf)}).execute(input)
+		return splitLines(buf.String()), err
+	}
+	cmd, args, err := s.lookup(input)
+	if cmd == nil {
+		return nil, err
+	}
+	return cmd.Execute(s.fs, args)
+}
+
+// same as Execute, but output lines are written to w, each ended with a newline
+// commands implementing StreamingCommand write them while they run
+// commands nested in statements are echoed before their output, the first failing one ends the statement
+// returns error of the command or of the writer
+func (s *Shell) ExecuteTo(w io.Writer, input string) error {
+	input, err := s.prepare(input, nil)
+	if err != nil {
+		return err
+	}
+	return (&runner{s: s, w: newOutputWriter(w)}).execute(input)
+}
+
+// checks columns of the input line if the shell is set so and expands variables and arguments in it,
+// columns are checked before expansion, as they are written in the input
+func (s *Shell) prepare(input string, args []string) (string, error) {
+	if s.opts.StrictColumns && !isComment(input) {
+		tokens, err := tokenize(input)
+		if err == nil {
+			err = checkColumns(input, tokens)
+		}
+		if err != nil {
+			return input, err
+		}
+	}
+	return s.expand(input, args), nil
+}
+
+// same as ExecuteTo for input returned by prepare
+func (s *Shell) executeTo(w io.Writer, input string) error {
+	cmd, args, err := s.lookup(input)
+	if cmd == nil {
+		return err
+	}
+	if streaming, ok := cmd.(StreamingCommand); ok {
+		return streaming.ExecuteTo(w, s.fs, args)
+	}
+	output, err := cmd.Execute(s.fs, args)
+	if writeErr := writeLines(w, output); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+// returns command of the input line with its arguments checked against its arity
+// returns nil command for empty input and comments
+func (s *Shell) lookup(input string) (Command, []string, error) {
+	if isComment(input) {
+		return nil, nil, nil
+	}
+	name, args, err := splitInput(input)
+	if err != nil || name == "" {
+		return nil, nil, err
+	}
+	cmd, ok := s.commands.lookup(name)
+	if !ok {
+		return nil, nil, ErrUnknownCommand
+	}
+
+	min, max := cmd.Arity()
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		return nil, nil, fmt.Errorf("%w, usage: %s", ErrWrongNumberOfArguments, cmd.Usage())
+	}
+	return cmd, args, nil
+}
+
+// executes commands read line by line and writes echo of each command followed by its output
+// echo shows the command with variables expanded, comments are echoed only if the shell is set so
+// commands nested in statements are echoed and handled by the policy the same way
+// returns error of reading the input, error of writing the output, which stops the run whatever the policy is,
+// or, in strict mode, error of the first failed command with its line number
+func (s *Shell) Run(r io.Reader, w io.Writer, policy ErrorPolicy) (err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanLines)
+	writer := bufio.NewWriter(w)
+	defer func() {
+		if flushErr := writer.Flush(); err == nil {
+			err = flushErr
+		}
+	}()
+
+	script := &runner{s: s, w: newOutputWriter(writer), report: true, policy: policy}
+	line := 0
+	for scanner.Scan() {
+		line++
+		err := script.runLine(scanner.Text())
+		if err == nil {
+			continue
+		}
+		if script.w.err != nil {
+			return script.w.err
+		}
+		if policy == StrictOnError {
+			return &LineError{Line: line, Err: errors.Unwrap(err)}
+		}
+		return nil
+	}
+	return scanner.Err()
+}
+
+// returns line written before the output of the command in Run
+// first argument starts in column 18 and second in column 26
+// arguments are echoed as written, with their quotes, comments are echoed whole as written
+func CommandEcho(input string) string {
+	chunks, _ := tokenize(input)
+	if len(chunks) == 0 {
+		return ""
+	}
+	if isComment(input) {
+		return "Command: " + strings.TrimSpace(input)
+	}
+	echo := fmt.Sprintf("Command: %s", chunks[0].raw)
+	if len(chunks) > 1 {
+		// first argument in column 18, or after a space if the command is too long
+		echo = fmt.Sprintf("%-16s %s", echo, chunks[1].raw)
+	}
+	if len(chunks) > 2 {
+		// second argument in column 26, or after a space if the first one is too long
+		echo = fmt.Sprintf("%-24s %s", echo, chunks[2].raw)
+	}
+	// remaining arguments separated by single space
+	for i := 3; i < len(chunks); i++ {
+		echo += " " + chunks[i].raw
+	}
+
+	return echo
+}
+
+func writeLines(w io.Writer, lines []string) error {
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splits output ended with a newline into lines
+func splitLines(output string) []string {
+	if output == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+}
@@ -0,0 +1,153 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+func TestShellRun(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		policy          ErrorPolicy
+		expectedOutput  string
+		expectedErrLine int
+	}{
+		{
+			name:   "continue on error",
+			policy: ContinueOnError,
+			expectedOutput: "Command: mkdir   sub1\n" +
+				"Command: cd      sub2\n" +
+				"Subdirectory does not exist\n" +
+				"Command: dir\n" +
+				"Directory of root:\n" +
+				"sub1\n",
+		},
+		{
+			name:   "stop on error",
+			policy: StopOnError,
+			expectedOutput: "Command: mkdir   sub1\n" +
+				"Command: cd      sub2\n" +
+				"Subdirectory does not exist\n",
+		},
+		{
+			name:   "strict",
+			policy: StrictOnError,
+			expectedOutput: "Command: mkdir   sub1\n" +
+				"Command: cd      sub2\n" +
+				"Subdirectory does not exist\n",
+			expectedErrLine: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := strings.NewReader("mkdir   sub1\ncd      sub2\ndir\n")
+			var output bytes.Buffer
+			err := New(vfs.New(), Options{}).Run(input, &output, tt.policy)
+			var lineErr *LineError
+			if tt.expectedErrLine == 0 && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectedErrLine != 0 && (!errors.As(err, &lineErr) || lineErr.Line != tt.expectedErrLine) {
+				t.Fatalf("expected error in line %d, got: %v", tt.expectedErrLine, err)
+			}
+			if diff := cmp.Diff(tt.expectedOutput, output.String()); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestShellRunReturnsWriteError(t *testing.T) {
+	t.Parallel()
+	for _, policy := range []ErrorPolicy{ContinueOnError, StopOnError, StrictOnError} {
+		err := New(vfs.New(), Options{}).Run(strings.NewReader("mkdir   sub1\ndir\n"), &failingWriter{}, policy)
+		if !errors.Is(err, errWriteFailed) {
+			t.Fatalf("error mismatch for policy %v:\nwant: %v\ngot: %v", policy, errWriteFailed, err)
+		}
+	}
+}
+
+func TestShellRunStopsOnWriteError(t *testing.T) {
+	t.Parallel()
+	var input strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&input, "mkdir   sub%d\n", i)
+	}
+	fs := vfs.New()
+	err := New(fs, Options{}).Run(strings.NewReader(input.String()), &failingWriter{}, ContinueOnError)
+	if !errors.Is(err, errWriteFailed) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", errWriteFailed, err)
+	}
+	if len(fs.Root().Subdirs()) == 1000 {
+		t.Fatalf("run continued after the output failed")
+	}
+}
+
+func TestParseErrorPolicy(t *testing.T) {
+	t.Parallel()
+	for name, expected := range map[string]ErrorPolicy{
+		"continue": ContinueOnError,
+		"stop":     StopOnError,
+		"strict":   StrictOnError,
+	} {
+		policy, err := ParseErrorPolicy(name)
+		if err != nil || policy != expected {
+			t.Fatalf("policy %s mismatch:\nwant: %v\ngot: %v, %v", name, expected, policy, err)
+		}
+	}
+	if _, err := ParseErrorPolicy("ignore"); !errors.Is(err, ErrUnknownErrorPolicy) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", ErrUnknownErrorPolicy, err)
+	}
+}
+
+func TestShellExecuteTo(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		input          string
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name:  "streaming command",
+			input: "tree",
+			expectedOutput: "Tree of root:\n" +
+				".\n" +
+				"└── sub1\n",
+		},
+		{
+			name:  "command returning lines",
+			input: "dir",
+			expectedOutput: "Directory of root:\n" +
+				"sub1\n",
+		},
+		{
+			name:        "unknown command",
+			input:       "notacommand",
+			expectedErr: ErrUnknownCommand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := vfs.New()
+			fs.AddSubdir("sub1")
+			var output bytes.Buffer
+			err := New(fs, Options{}).ExecuteTo(&output, tt.input)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			if diff := cmp.Diff(tt.expectedOutput, output.String()); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnterminatedQuote = errors.New("Unterminated quote")
+	ErrTrailingEscape    = errors.New("Escape character at the end of the line")
+	ErrMisalignedColumns = errors.New("Misaligned columns")
+)
+
+// token of the input line
+type token struct {
+	// text as written in the input, with quotes and escape characters
+	raw string
+	// text with quotes and escape characters removed
+	value string
+	// offset of the token in the input
+	start int
+}
+
+// splits input line into tokens separated by whitespace, as many as needed, so that column-aligned input works
+// text in double quotes belongs to a single token and may contain whitespace, the quotes are removed,
+// e.g. "My Docs" or My" "Docs are both My Docs and "" is an empty token
+// ^ escapes the next character, also within quotes, e.g. ^" is a quote and ^^ is ^
+// returns error if a quote is not closed or the line ends with ^, tokens still cover the whole line
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	var value strings.Builder
+	var err error
+	// start of the current token in the input, -1 between tokens
+	start := -1
+	quoted := false
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		if start < 0 {
+			if isSpace(c) {
+				continue
+			}
+			start = i
+			value.Reset()
+		}
+		switch {
+		case c == '^' && i+1 == len(input):
+			err = ErrTrailingEscape
+		case c == '^':
+			i++
+			value.WriteByte(input[i])
+		case c == '"':
+			quoted = !quoted
+		case isSpace(c) && !quoted:
+			tokens = append(tokens, token{raw: input[start:i], value: value.String(), start: start})
+			start = -1
+		default:
+			value.WriteByte(c)
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{raw: input[start:], value: value.String(), start: start})
+	}
+	if quoted {
+		err = ErrUnterminatedQuote
+	}
+	return tokens, err
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
+
+// returns name of the command and its arguments, empty name for empty input
+func splitInput(input string) (string, []string, error) {
+	tokens, err := tokenize(input)
+	if err != nil || len(tokens) == 0 {
+		return "", nil, err
+	}
+	args := make([]string, 0, len(tokens)-1)
+	for _, t := range tokens[1:] {
+		args = append(args, t.value)
+	}
+	return tokens[0].value, args, nil
+}
+
+// columns where the command and its first argument start in the fixed-column format
+var inputColumns = []int{1, 9}
+
+// checks that the input is in the fixed-column format, e.g. "mv      sub1 sub2"
+// command starts in column 1 and first argument in column 9, unless the command is too long,
+// then the argument follows after a single space, remaining arguments have no fixed columns
+// tokens are separated only by spaces, trailing whitespace is allowed
+// columns are counted in characters
+func checkColumns(input string, tokens []token) error {
+	column, end := 1, 0
+	for i, t := range tokens {
+		if separator := input[end:t.start]; strings.Trim(separator, " ") != "" {
+			return fmt.Errorf("%w, %s must be preceded only by spaces", ErrMisalignedColumns, t.raw)
+		}
+		end = t.start + len(t.raw)
+		if i >= len(inputColumns) {
+			continue
+		}
+		expected := inputColumns[i]
+		if i > 0 && column+1 > expected {
+			// single space after the previous token
+			expected = column + 1
+		}
+		column = utf8.RuneCountInString(input[:t.start]) + 1
+		if column != expected {
+			return fmt.Errorf("%w, %s starts in column %d instead of %d", ErrMisalignedColumns, t.raw, column, expected)
+		}
+		// column right after the token
+		column += utf8.RuneCountInString(t.raw)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		input          string
+		expectedValues []string
+		expectedRaw    []string
+		expectedErr    error
+	}{
+		{
+			name:           "column-aligned input",
+			input:          "mv      sub1    sub2",
+			expectedValues: []string{"mv", "sub1", "sub2"},
+			expectedRaw:    []string{"mv", "sub1", "sub2"},
+		},
+		{
+			name:           "single spaces and tabs",
+			input:          " mv sub1\tsub2 ",
+			expectedValues: []string{"mv", "sub1", "sub2"},
+			expectedRaw:    []string{"mv", "sub1", "sub2"},
+		},
+		{
+			name:           "quoted name with spaces",
+			input:          `mv "My Docs" Archive`,
+			expectedValues: []string{"mv", "My Docs", "Archive"},
+			expectedRaw:    []string{"mv", `"My Docs"`, "Archive"},
+		},
+		{
+			name:           "quotes inside token",
+			input:          `cd My" "Docs\"sub  1"`,
+			expectedValues: []string{"cd", `My Docs\sub  1`},
+			expectedRaw:    []string{"cd", `My" "Docs\"sub  1"`},
+		},
+		{
+			name:           "empty quoted token",
+			input:          `append a.txt ""`,
+			expectedValues: []string{"append", "a.txt", ""},
+			expectedRaw:    []string{"append", "a.txt", `""`},
+		},
+		{
+			name:           "escapes",
+			input:          `append a.txt ^"quoted^" "^^ and ^" inside" a^ b`,
+			expectedValues: []string{"append", "a.txt", `"quoted"`, `^ and " inside`, "a b"},
+			expectedRaw:    []string{"append", "a.txt", `^"quoted^"`, `"^^ and ^" inside"`, "a^ b"},
+		},
+		{
+			name:           "unterminated quote",
+			input:          `mkdir "My Docs`,
+			expectedValues: []string{"mkdir", "My Docs"},
+			expectedRaw:    []string{"mkdir", `"My Docs`},
+			expectedErr:    ErrUnterminatedQuote,
+		},
+		{
+			name:           "trailing escape",
+			input:          `mkdir sub^`,
+			expectedValues: []string{"mkdir", "sub"},
+			expectedRaw:    []string{"mkdir", "sub^"},
+			expectedErr:    ErrTrailingEscape,
+		},
+		{
+			name:  "whitespace only",
+			input: " \t ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := tokenize(tt.input)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			var values, raw []string
+			for _, token := range tokens {
+				values = append(values, token.value)
+				raw = append(raw, token.raw)
+			}
+			if diff := cmp.Diff(tt.expectedValues, values); diff != "" {
+				t.Fatalf("values mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.expectedRaw, raw); diff != "" {
+				t.Fatalf("raw tokens mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuotedArguments(t *testing.T) {
+	t.Parallel()
+	fs := vfs.New()
+	sh := New(fs, Options{})
+	for _, input := range []string{
+		`mkdir   "My Docs"`,
+		`mkdir   Archive`,
+		`append  "My Docs\notes  1.txt"   "two  spaces"`,
+		`mv      "My Docs"       Archive`,
+		`cd      "Archive\My Docs"`,
+	} {
+		if _, err := sh.Execute(input); err != nil {
+			t.Fatalf("unexpected error of %s: %v", input, err)
+		}
+	}
+	output, err := sh.Execute(`type "notes  1.txt"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"two  spaces"}, output); diff != "" {
+		t.Fatalf("output mismatch (-want +got):\n%s", diff)
+	}
+	if fs.Current().Path() != `root\Archive\My Docs` {
+		t.Fatalf("current dir mismatch:\nwant: %s\ngot: %s", `root\Archive\My Docs`, fs.Current().Path())
+	}
+
+	if _, err := sh.Execute(`mkdir "sub1`); !errors.Is(err, ErrUnterminatedQuote) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", ErrUnterminatedQuote, err)
+	}
+}
+
+func TestCommandEcho(t *testing.T) {
+	t.Parallel()
+	for input, expected := range map[string]string{
+		"":                          "",
+		"dir":                       "Command: dir",
+		"cd sub1":                   "Command: cd      sub1",
+		"mv   sub1  sub2":           "Command: mv      sub1    sub2",
+		`mv "My Docs" Archive`:      `Command: mv      "My Docs" Archive`,
+		"mv longsubdir sub2":        "Command: mv      longsubdir sub2",
+		`append a.txt some text ^^`: "Command: append  a.txt   some text ^^",
+	} {
+		if echo := CommandEcho(input); echo != expected {
+			t.Fatalf("echo of %q mismatch:\nwant: %q\ngot: %q", input, expected, echo)
+		}
+	}
+}
+
+func TestStrictColumns(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input       string
+		expectedErr error
+	}{
+		{input: "dir"},
+		{input: "cd      sub1"},
+		{input: "mv      sub1    sub2"},
+		{input: "mv      sub1    sub2  "},
+		{input: "mv      longsubdir sub2"},
+		{input: `mv      "My Docs" Archive`},
+		{input: "append  a.txt   some text"},
+		{input: "append  a.txt some  text"},
+		{input: "mv      sub601 ..\\sub4"},
+		{input: "mv      longsubdir  sub2"},
+		{input: "rmdir   /s sub1"},
+		{input: "tree    sub1    /f /l 2"},
+		{input: "ünicode sub1"},
+		{input: ""},
+		{input: "cd sub1", expectedErr: ErrMisalignedColumns},
+		{input: " dir", expectedErr: ErrMisalignedColumns},
+		{input: "mv     sub1 sub2", expectedErr: ErrMisalignedColumns},
+		{input: "mv      sub1\tsub2", expectedErr: ErrMisalignedColumns},
+		{input: "cd\t\tsub1", expectedErr: ErrMisalignedColumns},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tokens, err := tokenize(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := checkColumns(tt.input, tokens); !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestShellRunStrictColumns(t *testing.T) {
+	t.Parallel()
+	input := strings.NewReader("mkdir   sub1\ncd sub1\ndir\n")
+	var output bytes.Buffer
+	err := New(vfs.New(), Options{StrictColumns: true}).Run(input, &output, StrictOnError)
+	var lineErr *LineError
+	if !errors.As(err, &lineErr) || lineErr.Line != 2 || !errors.Is(err, ErrMisalignedColumns) {
+		t.Fatalf("expected misaligned columns in line 2, got: %v", err)
+	}
+	expectedOutput := "Command: mkdir   sub1\n" +
+		"Command: cd      sub1\n" +
+		"Misaligned columns, sub1 starts in column 4 instead of 9\n"
+	if diff := cmp.Diff(expectedOutput, output.String()); diff != "" {
+		t.Fatalf("output mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,256 @@
+package shell
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+// draws tree of given directory, current directory by default
+// /f lists also files, /d only directories, /l limits depth, /p keeps only entries matching the pattern
+// and their ancestors, /s ends the tree with numbers of listed directories and files,
+// /a draws the tree with ASCII characters, which is the default if the shell is set so
+func (s *Shell) handleTree(w io.Writer, fs *vfs.Filesystem, args []string) error {
+	opts := TreeOptions{ASCII: s.opts.ASCIITree}
+	dirsOnly := false
+	path := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case strings.EqualFold(arg, "/f"):
+			opts.ShowFiles = true
+		case strings.EqualFold(arg, "/d"):
+			dirsOnly = true
+		case strings.EqualFold(arg, "/s"):
+			opts.Summary = true
+		case strings.EqualFold(arg, "/a"):
+			opts.ASCII = true
+		case strings.EqualFold(arg, "/l"):
+			if i+1 == len(args) {
+				return ErrWrongNumberOfArguments
+			}
+			i++
+			depth, err := strconv.Atoi(args[i])
+			if err != nil || depth < 1 {
+				return fmt.Errorf("%w, depth must be a positive number: %s", ErrInvalidSwitch, args[i])
+			}
+			opts.MaxDepth = depth
+		case strings.EqualFold(arg, "/p"):
+			if i+1 == len(args) {
+				return ErrWrongNumberOfArguments
+			}
+			i++
+			opts.Pattern = args[i]
+		case strings.HasPrefix(arg, "/"):
+			return ErrInvalidSwitch
+		case path != "":
+			return ErrWrongNumberOfArguments
+		default:
+			path = arg
+		}
+	}
+	if dirsOnly && opts.ShowFiles {
+		return fmt.Errorf("%w, /d cannot be used with /f", ErrInvalidSwitch)
+	}
+
+	target := fs.Current()
+	if path != "" {
+		var err error
+		target, err = fs.ResolveDir(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return WriteTree(w, target, opts)
+}
+
+// options of the tree drawing
+type TreeOptions struct {
+	// list files besides subdirectories
+	ShowFiles bool
+	// maximal depth of listed entries, entries of the directory have depth 1, 0 means no limit
+	MaxDepth int
+	// list only entries with names matching the pattern and directories containing them,
+	// pattern has DOS wildcards * and ?, as in vfs.MatchWildcard, empty pattern matches all names
+	Pattern string
+	// end the tree with numbers of listed directories and files, e.g. "2 directories, 1 file"
+	Summary bool
+	// draw branches with +---, \--- and | instead of box-drawing characters
+	ASCII bool
+}
+
+// draws tree of the directory with header line and connectors of the branches
+func RenderTree(d *vfs.Dir, opts TreeOptions) []string {
+	var buf bytes.Buffer
+	WriteTree(&buf, d, opts)
+	return splitLines(buf.String())
+}
+
+// writes the same lines as RenderTree one by one, memory used depends only on depth of the tree
+// unless a pattern is given, then directories with matching entries are remembered
+// returns the first error of the writer
+func WriteTree(w io.Writer, d *vfs.Dir, opts TreeOptions) error {
+	t := &treeWriter{w: w, opts: opts}
+	if opts.Pattern != "" {
+		t.kept = map[*vfs.Dir]bool{}
+		t.markKept(d, 0)
+	}
+	t.writeLine("Tree of " + d.Path() + ":")
+	t.writeLine(".")
+	t.writeBranches(d, 0)
+	if opts.Summary {
+		t.writeLine(t.summary())
+	}
+	return t.err
+}
+
+// writes lines of the tree, prefix holds connectors of the ancestors of the current line
+type treeWriter struct {
+	w      io.Writer
+	opts   TreeOptions
+	prefix []byte
+	line   []byte
+	err    error
+	// subdirectories listed despite the pattern, nil without pattern
+	kept  map[*vfs.Dir]bool
+	dirs  int
+	files int
+}
+
+// files of the directory are listed before its subdirectories
+func (t *treeWriter) writeBranches(current *vfs.Dir, depth int) {
+	if t.opts.MaxDepth > 0 && depth >= t.opts.MaxDepth {
+		return
+	}
+	var files []*vfs.File
+	if t.opts.ShowFiles {
+		files = t.filterFiles(current.Files())
+	}
+	subdirs := t.filterSubdirs(current.Subdirs())
+	entries := len(files) + len(subdirs)
+
+	for i, f := range files {
+		t.writeBranch(i == entries-1, f.Name())
+		t.files++
+	}
+	for i, subdir := range subdirs {
+		if t.err != nil {
+			return
+		}
+		isLast := len(files)+i == entries-1
+		t.writeBranch(isLast, subdir.Name())
+		t.dirs++
+
+		prefixLength := len(t.prefix)
+		t.prefix = append(t.prefix, getTreeIndent(isLast, t.opts.ASCII)...)
+		t.writeBranches(subdir, depth+1)
+		t.prefix = t.prefix[:prefixLength]
+	}
+}
+
+// marks subdirectories which match the pattern or contain matching entries within the depth limit
+// returns whether any entry of the directory is listed
+func (t *treeWriter) markKept(current *vfs.Dir, depth int) bool {
+	if t.opts.MaxDepth > 0 && depth >= t.opts.MaxDepth {
+		return false
+	}
+	found := t.opts.ShowFiles && len(t.filterFiles(current.Files())) > 0
+	for _, subdir := range current.Subdirs() {
+		if t.markKept(subdir, depth+1) || t.matches(subdir.Name()) {
+			t.kept[subdir] = true
+			found = true
+		}
+	}
+	return found
+}
+
+func (t *treeWriter) filterFiles(files []*vfs.File) []*vfs.File {
+	if t.opts.Pattern == "" {
+		return files
+	}
+	filtered := []*vfs.File{}
+	for _, f := range files {
+		if t.matches(f.Name()) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+func (t *treeWriter) filterSubdirs(subdirs []*vfs.Dir) []*vfs.Dir {
+	if t.kept == nil {
+		return subdirs
+	}
+	filtered := []*vfs.Dir{}
+	for _, subdir := range subdirs {
+		if t.kept[subdir] {
+			filtered = append(filtered, subdir)
+		}
+	}
+	return filtered
+}
+
+func (t *treeWriter) matches(name string) bool {
+	return vfs.MatchWildcard(t.opts.Pattern, name)
+}
+
+// numbers of listed entries, files are counted only if they are listed
+func (t *treeWriter) summary() string {
+	summary := pluralize(t.dirs, "directory", "directories")
+	if t.opts.ShowFiles {
+		summary += ", " + pluralize(t.files, "file", "files")
+	}
+	return summary
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+func (t *treeWriter) writeBranch(isLast bool, name string) {
+	t.line = append(t.line[:0], t.prefix...)
+	t.line = append(t.line, getTreeConnector(isLast, t.opts.ASCII)...)
+	t.writeLine(name)
+}
+
+// writes the name after what is already in the line
+func (t *treeWriter) writeLine(name string) {
+	if t.err != nil {
+		return
+	}
+	t.line = append(t.line, name...)
+	t.line = append(t.line, '\n')
+	_, t.err = t.w.Write(t.line)
+	t.line = t.line[:0]
+}
+
+func getTreeConnector(isLast, ascii bool) string {
+	switch {
+	case isLast && ascii:
+		return "\\---"
+	case ascii:
+		return "+---"
+	case isLast:
+		return "└── "
+	}
+	return "├── "
+}
+
+// prefix of the entries of a subdirectory, continues the branches of its parent
+func getTreeIndent(isLast, ascii bool) string {
+	switch {
+	case isLast:
+		return "    "
+	case ascii:
+		return "|   "
+	}
+	return "│   "
+}
@@ -0,0 +1,452 @@
+package shell
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+func TestHandleTree(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		switches       string
+		fs             func() *vfs.Filesystem
+		expectedOutput []string
+		expectedErr    error
+	}{
+		{
+
+			name: "root with no subdirs",
+			fs:   vfs.New,
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+			},
+		},
+		{
+			name: "root with 3 subdir levels",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.Cd(fs.Current().Subdirs()[0].Name())
+				fs.AddSubdir("sub3")
+				fs.Cd(fs.Current().Subdirs()[0].Name())
+				fs.AddSubdir("sub4")
+				fs.Cd("\\")
+				return fs
+			},
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+				"├── sub1",
+				"│   └── sub3",
+				"│       └── sub4",
+				"└── sub2",
+			},
+		},
+		{
+			name: "root with 3 subdir levels with more subdirs",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Cd(fs.Current().Subdirs()[0].Name())
+				fs.AddSubdir("sub3")
+				fs.Cd(fs.Current().Subdirs()[0].Name())
+				fs.AddSubdir("sub4")
+				fs.AddSubdir("sub5")
+				fs.Cd("\\")
+				return fs
+			},
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+				"└── sub1",
+				"    └── sub3",
+				"        ├── sub4",
+				"        └── sub5",
+			},
+		},
+		{
+
+			name: "subdir with no subdirs",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.Cd(fs.Current().Subdirs()[0].Name())
+				return fs
+			},
+			expectedOutput: []string{
+				"Tree of root\\sub1:",
+				".",
+			},
+		},
+		{
+			name: "root with 2 subdir levels one subdir per level",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Cd(fs.Current().Subdirs()[0].Name())
+				fs.AddSubdir("sub3")
+				fs.Cd(fs.Current().Subdirs()[0].Name())
+				fs.AddSubdir("sub4")
+				fs.Cd("\\")
+				return fs
+			},
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+				"└── sub1",
+				"    └── sub3",
+				"        └── sub4",
+			},
+		},
+		{
+			name: "root with complicated subdir levels",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.Cd(fs.Current().Subdirs()[0].Name())
+				fs.AddSubdir("sub3")
+				fs.AddSubdir("sub4")
+				fs.Cd(fs.Current().Subdirs()[0].Name())
+				fs.AddSubdir("sub4")
+				fs.Cd("\\")
+				return fs
+			},
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+				"├── sub1",
+				"│   ├── sub3",
+				"│   │   └── sub4",
+				"│   └── sub4",
+				"└── sub2",
+			},
+		},
+		{
+			name: "files are not listed by default",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.Touch("file1.txt")
+				return fs
+			},
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+				"└── sub1",
+			},
+		},
+		{
+			name:     "files listed before subdirs",
+			switches: "/F",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1")
+				fs.AddSubdir("sub2")
+				fs.Touch("file1.txt")
+				fs.Cd("sub1")
+				fs.Touch("file2.txt")
+				fs.Touch("file3.txt")
+				fs.Cd("\\")
+				fs.Cd("sub2")
+				fs.Touch("file4.txt")
+				fs.Cd("\\")
+				return fs
+			},
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+				"├── file1.txt",
+				"├── sub1",
+				"│   ├── file2.txt",
+				"│   └── file3.txt",
+				"└── sub2",
+				"    └── file4.txt",
+			},
+		},
+		{
+			name:     "tree of given path",
+			switches: "sub1 /f",
+			fs: func() *vfs.Filesystem {
+				fs := vfs.New()
+				fs.AddSubdir("sub1\\sub2")
+				fs.Cd("sub1")
+				fs.Touch("file1.txt")
+				fs.Cd("\\")
+				return fs
+			},
+			expectedOutput: []string{
+				"Tree of root\\sub1:",
+				".",
+				"├── file1.txt",
+				"└── sub2",
+			},
+		},
+		{
+			name:           "invalid switch",
+			switches:       "/x",
+			fs:             vfs.New,
+			expectedOutput: nil,
+			expectedErr:    ErrInvalidSwitch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := tt.fs()
+			output, err := execute("tree    "+tt.switches, fs)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				for _, o := range output {
+					t.Log(o)
+				}
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// tree which needs both kinds of prefixes of the ancestors
+func createTreeFixture() *vfs.Filesystem {
+	fs := vfs.New()
+	fs.AddSubdir("sub1\\sub3\\sub5")
+	fs.AddSubdir("sub1\\sub4")
+	fs.AddSubdir("sub2\\sub6\\sub7")
+	fs.Touch("sub1\\sub3\\file1.txt")
+	fs.Touch("sub2\\file2.txt")
+	return fs
+}
+
+func TestWriteTree(t *testing.T) {
+	t.Parallel()
+	fs := createTreeFixture()
+	var output strings.Builder
+	if err := WriteTree(&output, fs.Root(), TreeOptions{ShowFiles: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Tree of root:\n" +
+		".\n" +
+		"├── sub1\n" +
+		"│   ├── sub3\n" +
+		"│   │   ├── file1.txt\n" +
+		"│   │   └── sub5\n" +
+		"│   └── sub4\n" +
+		"└── sub2\n" +
+		"    ├── file2.txt\n" +
+		"    └── sub6\n" +
+		"        └── sub7\n"
+	if diff := cmp.Diff(expected, output.String()); diff != "" {
+		t.Fatalf("output mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(splitLines(expected), RenderTree(fs.Root(), TreeOptions{ShowFiles: true})); diff != "" {
+		t.Fatalf("rendered tree mismatch (-want +got):\n%s", diff)
+	}
+}
+
+var errWriteFailed = errors.New("write failed")
+
+// fails after given number of writes
+type failingWriter struct {
+	writesLeft int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.writesLeft == 0 {
+		return 0, errWriteFailed
+	}
+	w.writesLeft--
+	return len(p), nil
+}
+
+func TestWriteTreeStopsOnWriteError(t *testing.T) {
+	t.Parallel()
+	w := &failingWriter{writesLeft: 3}
+	err := WriteTree(w, createTreeFixture().Root(), TreeOptions{})
+	if !errors.Is(err, errWriteFailed) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", errWriteFailed, err)
+	}
+}
+
+func TestHandleTreeOptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		switches       string
+		expectedOutput []string
+		expectedErr    error
+	}{
+		{
+			name:     "depth limit",
+			switches: "/l 2",
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+				"├── sub1",
+				"│   ├── sub3",
+				"│   └── sub4",
+				"└── sub2",
+				"    └── sub6",
+			},
+		},
+		{
+			name:     "pattern keeps matching files and their ancestors",
+			switches: "/f /p file1*",
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+				"└── sub1",
+				"    └── sub3",
+				"        └── file1.txt",
+			},
+		},
+		{
+			name:     "pattern matches directories",
+			switches: "/P s?b*7",
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+				"└── sub2",
+				"    └── sub6",
+				"        └── sub7",
+			},
+		},
+		{
+			name:     "pattern with depth limit",
+			switches: "/p sub7 /l 2",
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+			},
+		},
+		{
+			name:     "summary",
+			switches: "/f /s",
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+				"├── sub1",
+				"│   ├── sub3",
+				"│   │   ├── file1.txt",
+				"│   │   └── sub5",
+				"│   └── sub4",
+				"└── sub2",
+				"    ├── file2.txt",
+				"    └── sub6",
+				"        └── sub7",
+				"7 directories, 2 files",
+			},
+		},
+		{
+			name:     "summary of directories only",
+			switches: "sub1\\sub3 /d /s",
+			expectedOutput: []string{
+				"Tree of root\\sub1\\sub3:",
+				".",
+				"└── sub5",
+				"1 directory",
+			},
+		},
+		{
+			name:        "dirs only with files",
+			switches:    "/d /f",
+			expectedErr: ErrInvalidSwitch,
+		},
+		{
+			name:        "depth not a number",
+			switches:    "/l two",
+			expectedErr: ErrInvalidSwitch,
+		},
+		{
+			name:        "depth zero",
+			switches:    "/l 0",
+			expectedErr: ErrInvalidSwitch,
+		},
+		{
+			name:        "depth missing",
+			switches:    "/l",
+			expectedErr: ErrWrongNumberOfArguments,
+		},
+		{
+			name:     "brackets in pattern match literally",
+			switches: "/f /p sub[4]",
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+			},
+		},
+		{
+			name:     "backslash in pattern matches literally",
+			switches: "/f /p \\",
+			expectedOutput: []string{
+				"Tree of root:",
+				".",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := execute("tree    "+tt.switches, createTreeFixture())
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleTreeASCII(t *testing.T) {
+	t.Parallel()
+	expectedOutput := []string{
+		"Tree of root:",
+		".",
+		"+---sub1",
+		"|   +---sub3",
+		"|   |   +---file1.txt",
+		"|   |   \\---sub5",
+		"|   \\---sub4",
+		"\\---sub2",
+		"    +---file2.txt",
+		"    \\---sub6",
+		"        \\---sub7",
+	}
+	tests := []struct {
+		name  string
+		input string
+		opts  Options
+	}{
+		{
+			name:  "switch",
+			input: "tree    /f      /A",
+		},
+		{
+			name:  "shell option",
+			input: "tree    /f",
+			opts:  Options{ASCIITree: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := New(createTreeFixture(), tt.opts).Execute(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(expectedOutput, output); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
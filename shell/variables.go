@@ -0,0 +1,142 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+var (
+	ErrInvalidVariableName = errors.New("Invalid variable name")
+	ErrVariableNotDefined  = errors.New("Environment variable not defined")
+)
+
+// variable of the shell, names are case-insensitive, but keep the case they were set with
+type variable struct {
+	name  string
+	value string
+}
+
+// set NAME=value sets the variable, set NAME= removes it
+// set lists all variables and set PREFIX those with names starting with the prefix
+// value is the rest of the line, arguments are joined with single spaces unless quoted, e.g. set "NAME=a  b"
+func (s *Shell) handleSet(fs *vfs.Filesystem, args []string) ([]string, error) {
+	assignment := strings.Join(args, " ")
+	name, value, isAssignment := strings.Cut(assignment, "=")
+	if !isAssignment {
+		return s.listVariables(name)
+	}
+	if name == "" || strings.Contains(name, "%") {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidVariableName, name)
+	}
+	key := strings.ToUpper(name)
+	if value == "" {
+		delete(s.vars, key)
+		return nil, nil
+	}
+	s.vars[key] = variable{name: name, value: value}
+	return nil, nil
+}
+
+// lists variables sorted by name as NAME=value
+func (s *Shell) listVariables(prefix string) ([]string, error) {
+	prefix = strings.ToUpper(prefix)
+	keys := []string{}
+	for key := range s.vars {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 && prefix != "" {
+		return nil, fmt.Errorf("%w: %s", ErrVariableNotDefined, prefix)
+	}
+	sort.Strings(keys)
+	output := make([]string, 0, len(keys))
+	for _, key := range keys {
+		output = append(output, s.vars[key].name+"="+s.vars[key].value)
+	}
+	return output, nil
+}
+
+// returns value of the variable, built-in CD is the path of the current directory unless it is set
+func (s *Shell) variable(name string) (string, bool) {
+	key := strings.ToUpper(name)
+	if v, ok := s.vars[key]; ok {
+		return v.value, true
+	}
+	if key == "CD" {
+		return s.fs.Current().Path(), true
+	}
+	return "", false
+}
+
+// replaces %NAME% with the value of the variable and %% with %
+// references to variables which are not defined are left unchanged, as in the DOS prompt
+// in called scripts %0 to %9 are replaced with arguments, empty if missing, and %* with all but %0
+func (s *Shell) expand(input string, args []string) string {
+	if !strings.Contains(input, "%") {
+		return input
+	}
+	var expanded strings.Builder
+	for {
+		i := strings.IndexByte(input, '%')
+		if i < 0 {
+			break
+		}
+		expanded.WriteString(input[:i])
+		input = input[i+1:]
+		if strings.HasPrefix(input, "%") {
+			expanded.WriteByte('%')
+			input = input[1:]
+			continue
+		}
+		if args != nil && input != "" && (input[0] == '*' || isDigit(input[0])) {
+			expanded.WriteString(argument(args, input[0]))
+			input = input[1:]
+			continue
+		}
+		end := strings.IndexByte(input, '%')
+		if end < 0 {
+			expanded.WriteByte('%')
+			break
+		}
+		if value, ok := s.variable(input[:end]); ok {
+			expanded.WriteString(value)
+			input = input[end+1:]
+			continue
+		}
+		// closing % may open the next reference
+		expanded.WriteByte('%')
+	}
+	expanded.WriteString(input)
+	return expanded.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// returns argument referenced by %n or all but the first one for %*
+func argument(args []string, ref byte) string {
+	if ref == '*' {
+		return strings.Join(args[1:], " ")
+	}
+	if n := int(ref - '0'); n < len(args) {
+		return args[n]
+	}
+	return ""
+}
+
+// comment lines start with rem or #, they are not executed
+func isComment(input string) bool {
+	input = strings.TrimLeft(input, " \t")
+	if strings.HasPrefix(input, "#") {
+		return true
+	}
+	first, _, _ := strings.Cut(input, " ")
+	first, _, _ = strings.Cut(first, "\t")
+	return strings.EqualFold(first, "rem")
+}
@@ -0,0 +1,172 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+func TestHandleSet(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		inputs         []string
+		expectedOutput []string
+		expectedErr    error
+	}{
+		{
+			name:   "list variables sorted by name",
+			inputs: []string{"set Name=sub1", "set APP=web app", "set"},
+			expectedOutput: []string{
+				"APP=web app",
+				"Name=sub1",
+			},
+		},
+		{
+			name:           "list by prefix ignoring case",
+			inputs:         []string{"set name=sub1", "set NAMES=a b", "set APP=app", "set NAME"},
+			expectedOutput: []string{"name=sub1", "NAMES=a b"},
+		},
+		{
+			name:           "quoted value keeps spaces",
+			inputs:         []string{`set "NAME=a  b"`, "set NAME"},
+			expectedOutput: []string{"NAME=a  b"},
+		},
+		{
+			name:           "value containing =",
+			inputs:         []string{"set EQ=a=b", "set EQ"},
+			expectedOutput: []string{"EQ=a=b"},
+		},
+		{
+			name:           "set again replaces value",
+			inputs:         []string{"set NAME=sub1", "set name=sub2", "set NAME"},
+			expectedOutput: []string{"name=sub2"},
+		},
+		{
+			name:        "empty value removes variable",
+			inputs:      []string{"set NAME=sub1", "set NAME=", "set NAME"},
+			expectedErr: ErrVariableNotDefined,
+		},
+		{
+			name:        "missing name",
+			inputs:      []string{"set =sub1"},
+			expectedErr: ErrInvalidVariableName,
+		},
+		{
+			name:        "name with percent",
+			inputs:      []string{"set A%B=sub1"},
+			expectedErr: ErrInvalidVariableName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sh := New(vfs.New(), Options{})
+			var output []string
+			var err error
+			for _, input := range tt.inputs {
+				output, err = sh.Execute(input)
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+	fs := vfs.New()
+	fs.AddSubdir("sub1")
+	fs.Cd("sub1")
+	sh := New(fs, Options{Variables: map[string]string{"Name": "web", "EMPTY_PATH": "a\\b"}})
+	for input, expected := range map[string]string{
+		"mkdir   %NAME%":         "mkdir   web",
+		"mkdir   %name%-%Name%":  "mkdir   web-web",
+		"mkdir   %EMPTY_PATH%":   "mkdir   a\\b",
+		"append  a.txt %CD%":     "append  a.txt root\\sub1",
+		"append  a.txt 100%%":    "append  a.txt 100%",
+		"append  a.txt %%NAME%%": "append  a.txt %NAME%",
+		"append  a.txt %OTHER%":  "append  a.txt %OTHER%",
+		"append  a.txt %a%NAME%": "append  a.txt %aweb",
+		"append  a.txt 50% off":  "append  a.txt 50% off",
+	} {
+		if expanded := sh.expand(input, nil); expanded != expected {
+			t.Fatalf("expansion of %q mismatch:\nwant: %q\ngot: %q", input, expected, expanded)
+		}
+	}
+
+	// %CD% leads back to the directory even below a directory named as the root
+	fs.AddSubdir(".\\root")
+	sh.Execute("cd      .\\root")
+	sh.Execute("set     HERE=%CD%")
+	sh.Execute("up")
+	if _, err := sh.Execute("cd      %HERE%"); err != nil || fs.Current().Path() != "root\\sub1\\root" {
+		t.Fatalf("cd to %%CD%% mismatch, current: %s, error: %v", fs.Current().Path(), err)
+	}
+
+	sh.Execute("set CD=custom")
+	if expanded := sh.expand("%CD%", nil); expanded != "custom" {
+		t.Fatalf("set variable should override built-in one, got: %q", expanded)
+	}
+}
+
+func TestShellRunScript(t *testing.T) {
+	t.Parallel()
+	script := "rem     layout of a project\n" +
+		"set     PROJECT=web\n" +
+		"# sources\n" +
+		"mkdir   %PROJECT%\\src\n" +
+		"  REM   indented comment\n" +
+		"cd      %PROJECT%\n" +
+		"append  notes.txt %PROJECT% in %CD%\n" +
+		"type    notes.txt\n"
+	tests := []struct {
+		name           string
+		opts           Options
+		expectedOutput string
+	}{
+		{
+			name: "comments skipped",
+			expectedOutput: "Command: set     PROJECT=web\n" +
+				"Command: mkdir   web\\src\n" +
+				"Command: cd      web\n" +
+				"Command: append  notes.txt web in root\\web\n" +
+				"Command: type    notes.txt\n" +
+				"web in root\\web\n",
+		},
+		{
+			name: "comments echoed",
+			opts: Options{EchoComments: true, StrictColumns: true},
+			expectedOutput: "Command: rem     layout of a project\n" +
+				"Command: set     PROJECT=web\n" +
+				"Command: # sources\n" +
+				"Command: mkdir   web\\src\n" +
+				"Command: REM   indented comment\n" +
+				"Command: cd      web\n" +
+				"Command: append  notes.txt web in root\\web\n" +
+				"Command: type    notes.txt\n" +
+				"web in root\\web\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := New(vfs.New(), tt.opts).Run(strings.NewReader(script), &output, StrictOnError)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expectedOutput, output.String()); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
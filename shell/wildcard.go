@@ -0,0 +1,47 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+var (
+	ErrTooManyMatches = errors.New("Pattern matches more than one directory")
+)
+
+// expands wildcards of the path to paths of matching directories, files are skipped
+// path without wildcards is returned as it is, so that commands report their own errors for it
+// returns vfs.ErrNoMatch if no directory matches
+func expandDirs(fs *vfs.Filesystem, path string) ([]string, error) {
+	if !vfs.HasWildcards(path) {
+		return []string{path}, nil
+	}
+	matches, err := fs.ExpandWildcards(path)
+	if err != nil {
+		return nil, err
+	}
+	dirs := []string{}
+	for _, match := range matches {
+		if _, err := fs.ResolveDir(match); err == nil {
+			dirs = append(dirs, match)
+		}
+	}
+	if len(dirs) == 0 {
+		return nil, vfs.ErrNoMatch
+	}
+	return dirs, nil
+}
+
+// expands the path to a single directory, returns ErrTooManyMatches if the pattern is ambiguous
+func expandDir(fs *vfs.Filesystem, path string) (string, error) {
+	dirs, err := expandDirs(fs, path)
+	if err != nil {
+		return "", err
+	}
+	if len(dirs) > 1 {
+		return "", fmt.Errorf("%w: %s", ErrTooManyMatches, path)
+	}
+	return dirs[0], nil
+}
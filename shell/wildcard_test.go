@@ -0,0 +1,202 @@
+package shell
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubarydz/dir-simulator/vfs"
+)
+
+func TestWildcards(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		input          string
+		expectedOutput []string
+		expectedErr    error
+		// entries of the root and current directory after the input
+		expectedRoot    []string
+		expectedCurrent string
+	}{
+		{
+			name:            "dir lists matching entries",
+			input:           "dir     s*",
+			expectedOutput:  []string{"Directory of root:", "sub1    sub10   sub2", "Files:", "subs.txt"},
+			expectedRoot:    []string{"archive", "sub1", "sub10", "sub2", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "dir of other directory",
+			input:           "dir     /b      \\archive\\*",
+			expectedOutput:  []string{"old"},
+			expectedRoot:    []string{"archive", "sub1", "sub10", "sub2", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "dir without matches",
+			input:           "dir     x*",
+			expectedErr:     vfs.ErrNoMatch,
+			expectedRoot:    []string{"archive", "sub1", "sub10", "sub2", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "cd to single match",
+			input:           "cd      arch*",
+			expectedRoot:    []string{"archive", "sub1", "sub10", "sub2", "subs.txt"},
+			expectedCurrent: "root\\archive",
+		},
+		{
+			name:            "cd skips files",
+			input:           "cd      sub1?",
+			expectedRoot:    []string{"archive", "sub1", "sub10", "sub2", "subs.txt"},
+			expectedCurrent: "root\\sub10",
+		},
+		{
+			name:            "cd with several matches",
+			input:           "cd      sub*",
+			expectedErr:     ErrTooManyMatches,
+			expectedRoot:    []string{"archive", "sub1", "sub10", "sub2", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "mv matching directories",
+			input:           "mv      sub*    archive",
+			expectedRoot:    []string{"archive", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "mv single match renames",
+			input:           "mv      sub1?   sub3",
+			expectedRoot:    []string{"archive", "sub1", "sub2", "sub3", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "mv skips destination matching pattern",
+			input:           "mv      *       sub2",
+			expectedRoot:    []string{"sub2", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "mv several into missing directory",
+			input:           "mv      sub?    sub3",
+			expectedErr:     vfs.ErrSubdirDoesNotExist,
+			expectedRoot:    []string{"archive", "sub1", "sub10", "sub2", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "mv to ambiguous destination",
+			input:           "mv      sub10   sub?",
+			expectedErr:     ErrTooManyMatches,
+			expectedRoot:    []string{"archive", "sub1", "sub10", "sub2", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "rmdir matching directories",
+			input:           "rmdir   sub?",
+			expectedRoot:    []string{"archive", "sub10", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "rmdir stops at non-empty directory",
+			input:           "rd      *",
+			expectedErr:     vfs.ErrSubdirNotEmpty,
+			expectedRoot:    []string{"archive", "sub1", "sub10", "sub2", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "deltree matching directories",
+			input:           "deltree a*",
+			expectedRoot:    []string{"sub1", "sub10", "sub2", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "pattern matching only files",
+			input:           "rmdir   /s      *.txt",
+			expectedErr:     vfs.ErrNoMatch,
+			expectedRoot:    []string{"archive", "sub1", "sub10", "sub2", "subs.txt"},
+			expectedCurrent: "root",
+		},
+		{
+			name:            "wildcards before last component",
+			input:           "cd      a*\\old",
+			expectedErr:     vfs.ErrInvalidPath,
+			expectedRoot:    []string{"archive", "sub1", "sub10", "sub2", "subs.txt"},
+			expectedCurrent: "root",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := vfs.New()
+			fs.AddSubdir("archive\\old")
+			fs.AddSubdir("sub1")
+			fs.AddSubdir("sub2")
+			fs.AddSubdir("sub10")
+			fs.Touch("subs.txt")
+
+			output, err := execute(tt.input, fs)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			if diff := cmp.Diff(tt.expectedOutput, output); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+			root, _ := execute("dir     /b      \\", fs)
+			if diff := cmp.Diff(tt.expectedRoot, root); diff != "" {
+				t.Fatalf("root entries mismatch (-want +got):\n%s", diff)
+			}
+			if current := fs.Current().Path(); current != tt.expectedCurrent {
+				t.Fatalf("current directory mismatch:\nwant: %s\ngot: %s", tt.expectedCurrent, current)
+			}
+		})
+	}
+}
+
+func TestWildcardsMatchEntryNamedAsRoot(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		input           string
+		expectedCurrent string
+		expectedEntries []string
+	}{
+		{
+			name:            "cd",
+			input:           "cd      roo*",
+			expectedCurrent: "root\\x\\root",
+			expectedEntries: []string{"root"},
+		},
+		{
+			name:            "rmdir",
+			input:           "rmdir   ro*",
+			expectedCurrent: "root\\x",
+			expectedEntries: []string{},
+		},
+		{
+			name:            "deltree",
+			input:           "deltree ro*",
+			expectedCurrent: "root\\x",
+			expectedEntries: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := vfs.New()
+			fs.AddSubdir("x\\root")
+			fs.Cd("x")
+
+			if _, err := execute(tt.input, fs); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if current := fs.Current().Path(); current != tt.expectedCurrent {
+				t.Fatalf("current directory mismatch:\nwant: %s\ngot: %s", tt.expectedCurrent, current)
+			}
+			entries, _ := execute("dir     /b      \\x", fs)
+			if diff := cmp.Diff(tt.expectedEntries, entries); diff != "" {
+				t.Fatalf("entries of x mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
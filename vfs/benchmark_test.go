@@ -0,0 +1,85 @@
+package vfs
+
+import (
+	"fmt"
+	"testing"
+)
+
+var benchmarkSizes = []int{100_000, 1_000_000}
+
+// creates filesystem with directory "big" containing n subdirectories
+func createWideFilesystem(b *testing.B, n int) *Filesystem {
+	b.Helper()
+	fs := New()
+	for i := 0; i < n; i++ {
+		if err := fs.AddSubdir(fmt.Sprintf("big\\sub%d", i)); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return fs
+}
+
+// builds tree of n directories, each with up to 10 subdirectories
+func BenchmarkBuildTree(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("nodes=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				fs := New()
+				paths := []string{"root"}
+				for j := 1; j < n; j++ {
+					path := fmt.Sprintf("%s\\sub%d", paths[(j-1)/10], j)
+					if err := fs.AddSubdir(path); err != nil {
+						b.Fatalf("unexpected error: %v", err)
+					}
+					paths = append(paths, path)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMkdir(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("children=%d", n), func(b *testing.B) {
+			fs := createWideFilesystem(b, n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := fs.AddSubdir(fmt.Sprintf("big\\new%d", i)); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCd(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("children=%d", n), func(b *testing.B) {
+			fs := createWideFilesystem(b, n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := fs.Cd(fmt.Sprintf("\\big\\sub%d", i%n)); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// renames a directory back and forth, every iteration makes two moves
+func BenchmarkMv(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("children=%d", n), func(b *testing.B) {
+			fs := createWideFilesystem(b, n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := fs.Mv("big\\sub0", "big\\moved"); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+				if err := fs.Mv("big\\moved", "big\\sub0"); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
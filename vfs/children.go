@@ -0,0 +1,59 @@
+package vfs
+
+import (
+	"sort"
+	"sync"
+)
+
+// subdirectories or files of a directory indexed by name
+// adding, removing and finding a child takes constant time,
+// the sorted list is built only when it is read after a change
+// zero value is an empty index ready to use
+type children[T interface{ Name() string }] struct {
+	byName map[string]T
+	// guards building of the sorted list, so concurrent readers stay safe
+	mu sync.Mutex
+	// nil after a change, until the list is read again
+	sorted []T
+}
+
+func (c *children[T]) get(name string) (T, bool) {
+	child, ok := c.byName[name]
+	return child, ok
+}
+
+// the name of the child must not be taken
+func (c *children[T]) add(child T) {
+	if c.byName == nil {
+		c.byName = map[string]T{}
+	}
+	c.byName[child.Name()] = child
+	c.sorted = nil
+}
+
+// the child must be removed before its name changes
+func (c *children[T]) remove(child T) {
+	delete(c.byName, child.Name())
+	c.sorted = nil
+}
+
+func (c *children[T]) len() int {
+	return len(c.byName)
+}
+
+// returns children sorted by name, the slice must not be modified
+// it stays valid after later changes, which build a new one
+func (c *children[T]) list() []T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sorted == nil && len(c.byName) > 0 {
+		c.sorted = make([]T, 0, len(c.byName))
+		for _, child := range c.byName {
+			c.sorted = append(c.sorted, child)
+		}
+		sort.Slice(c.sorted, func(i, j int) bool {
+			return c.sorted[i].Name() < c.sorted[j].Name()
+		})
+	}
+	return c.sorted
+}
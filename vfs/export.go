@@ -0,0 +1,209 @@
+package vfs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	ErrExportTargetNotEmpty = errors.New("Export target is not empty")
+)
+
+// writes content of root to the target
+// target ending with .zip, .tar, .tar.gz or .tgz is written as archive, otherwise as directory
+// directory target must not exist or be empty
+// returns error if any entry would be written outside of the target
+func (fs *Filesystem) Export(target string) error {
+	switch {
+	case strings.HasSuffix(target, ".zip"):
+		return fs.exportArchive(target, newZipWriter)
+	case strings.HasSuffix(target, ".tar.gz"), strings.HasSuffix(target, ".tgz"):
+		return fs.exportArchive(target, newTarGzipWriter)
+	case strings.HasSuffix(target, ".tar"):
+		return fs.exportArchive(target, newTarWriter)
+	}
+	return fs.exportDir(target)
+}
+
+// visits every directory and file below root, parents before their content
+// path is relative to root and separated by slashes
+// returns error for paths that would escape the export target
+func (fs *Filesystem) walk(visit func(path string, d *Dir, f *File) error) error {
+	visitLocal := func(p string, d *Dir, f *File) error {
+		if !filepath.IsLocal(filepath.FromSlash(p)) {
+			return fmt.Errorf("%w: %s", ErrInvalidPath, p)
+		}
+		return visit(p, d, f)
+	}
+
+	var walkDir func(prefix string, d *Dir) error
+	walkDir = func(prefix string, d *Dir) error {
+		for _, f := range d.Files() {
+			if err := visitLocal(path.Join(prefix, f.name), nil, f); err != nil {
+				return err
+			}
+		}
+		for _, subdir := range d.Subdirs() {
+			subPath := path.Join(prefix, subdir.name)
+			if err := visitLocal(subPath, subdir, nil); err != nil {
+				return err
+			}
+			if err := walkDir(subPath, subdir); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walkDir("", fs.root)
+}
+
+func (fs *Filesystem) exportDir(target string) error {
+	entries, err := os.ReadDir(target)
+	if errors.Is(err, os.ErrNotExist) {
+		err = os.Mkdir(target, 0755)
+	} else if err == nil && len(entries) > 0 {
+		err = ErrExportTargetNotEmpty
+	}
+	if err != nil {
+		return err
+	}
+
+	return fs.walk(func(path string, d *Dir, f *File) error {
+		hostPath := filepath.Join(target, filepath.FromSlash(path))
+		if d != nil {
+			return os.Mkdir(hostPath, 0755)
+		}
+		// never follow anything that already exists under the target
+		hostFile, err := os.OpenFile(hostPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := hostFile.Write(f.content); err != nil {
+			hostFile.Close()
+			return err
+		}
+		return hostFile.Close()
+	})
+}
+
+// adds entries to an archive
+type archiveWriter interface {
+	addDir(path string, modTime time.Time) error
+	addFile(path string, content []byte, modTime time.Time) error
+	Close() error
+}
+
+func (fs *Filesystem) exportArchive(target string, newWriter func(w io.Writer) archiveWriter) error {
+	archiveFile, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer archiveFile.Close()
+
+	writer := newWriter(archiveFile)
+	// entries with unknown modification time get the time of the export
+	now := time.Now()
+	modTime := func(t time.Time) time.Time {
+		if t.IsZero() {
+			return now
+		}
+		return t
+	}
+	err = fs.walk(func(path string, d *Dir, f *File) error {
+		if d != nil {
+			return writer.addDir(path, modTime(d.modTime))
+		}
+		return writer.addFile(path, f.content, modTime(f.modTime))
+	})
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return archiveFile.Close()
+}
+
+type tarWriter struct {
+	*tar.Writer
+	// closed after the tar writer, if archive is compressed
+	compressed io.Closer
+}
+
+func newTarWriter(w io.Writer) archiveWriter {
+	return &tarWriter{Writer: tar.NewWriter(w)}
+}
+
+func newTarGzipWriter(w io.Writer) archiveWriter {
+	gzipWriter := gzip.NewWriter(w)
+	return &tarWriter{Writer: tar.NewWriter(gzipWriter), compressed: gzipWriter}
+}
+
+func (w *tarWriter) addDir(path string, modTime time.Time) error {
+	return w.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     path + "/",
+		Mode:     0755,
+		ModTime:  modTime,
+	})
+}
+
+func (w *tarWriter) addFile(path string, content []byte, modTime time.Time) error {
+	err := w.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     path,
+		Mode:     0644,
+		Size:     int64(len(content)),
+		ModTime:  modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+func (w *tarWriter) Close() error {
+	if err := w.Writer.Close(); err != nil {
+		return err
+	}
+	if w.compressed != nil {
+		return w.compressed.Close()
+	}
+	return nil
+}
+
+type zipWriter struct {
+	*zip.Writer
+}
+
+func newZipWriter(w io.Writer) archiveWriter {
+	return &zipWriter{zip.NewWriter(w)}
+}
+
+func (w *zipWriter) addDir(path string, modTime time.Time) error {
+	header := &zip.FileHeader{Name: path + "/", Modified: modTime}
+	header.SetMode(os.ModeDir | 0755)
+	_, err := w.CreateHeader(header)
+	return err
+}
+
+func (w *zipWriter) addFile(path string, content []byte, modTime time.Time) error {
+	header := &zip.FileHeader{Name: path, Method: zip.Deflate, Modified: modTime}
+	header.SetMode(0644)
+	entry, err := w.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = entry.Write(content)
+	return err
+}
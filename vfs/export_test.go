@@ -0,0 +1,175 @@
+package vfs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExportDir(t *testing.T) {
+	t.Parallel()
+	fs := createFSFixture()
+	target := filepath.Join(t.TempDir(), "out")
+	if err := fs.Export(target); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	imported, err := Import(target, ImportOptions{MaxDepth: -1, WithContent: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(listTree(fs), listTree(imported)); diff != "" {
+		t.Fatalf("tree mismatch (-want +got):\n%s", diff)
+	}
+	content, err := imported.ReadFile("sub1/b.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "second line\n" {
+		t.Fatalf("content mismatch:\nwant: %q\ngot: %q", "second line\n", content)
+	}
+}
+
+func TestExportDirTargetNotEmpty(t *testing.T) {
+	t.Parallel()
+	target := t.TempDir()
+	mustWriteFile(t, filepath.Join(target, "existing.txt"), "")
+	err := createFSFixture().Export(target)
+	if !errors.Is(err, ErrExportTargetNotEmpty) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", ErrExportTargetNotEmpty, err)
+	}
+}
+
+func TestExportRefusesPathsOutsideTarget(t *testing.T) {
+	t.Parallel()
+	fs := New()
+	// names are validated by the filesystem, so tree is corrupted directly
+	fs.root.addSubdir(&Dir{name: ".."})
+	fs.root.findSub("..").addFile(&File{name: "escaped.txt"})
+
+	base := t.TempDir()
+	target := filepath.Join(base, "out")
+	err := fs.Export(target)
+	if !errors.Is(err, ErrInvalidPath) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", ErrInvalidPath, err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "escaped.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("file written outside of the target")
+	}
+}
+
+func TestExportArchive(t *testing.T) {
+	t.Parallel()
+	expectedEntries := []string{
+		"a.txt: first line\n",
+		"sub1/",
+		"sub1/b.txt: second line\n",
+		"sub1/sub3/",
+		"sub1/sub3/empty.txt",
+		"sub2/",
+	}
+	tests := []struct {
+		name        string
+		target      string
+		readEntries func(t *testing.T, path string) []string
+	}{
+		{
+			name:        "tar",
+			target:      "out.tar",
+			readEntries: readTarEntries,
+		},
+		{
+			name:        "compressed tar",
+			target:      "out.tar.gz",
+			readEntries: readTarEntries,
+		},
+		{
+			name:        "zip",
+			target:      "out.zip",
+			readEntries: readZipEntries,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := filepath.Join(t.TempDir(), tt.target)
+			if err := createFSFixture().Export(target); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(expectedEntries, tt.readEntries(t, target)); diff != "" {
+				t.Fatalf("entries mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// returns names of the entries, followed by content for non-empty files
+func readTarEntries(t *testing.T, path string) []string {
+	t.Helper()
+	archiveFile, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archiveFile.Close()
+	var r io.Reader = archiveFile
+	if filepath.Ext(path) == ".gz" {
+		if r, err = gzip.NewReader(archiveFile); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries := []string{}
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return entries
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, formatEntry(header.Name, content))
+	}
+}
+
+func readZipEntries(t *testing.T, path string) []string {
+	t.Helper()
+	zipReader, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zipReader.Close()
+
+	entries := []string{}
+	for _, f := range zipReader.File {
+		entry, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(entry)
+		entry.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, formatEntry(f.Name, content))
+	}
+	return entries
+}
+
+func formatEntry(name string, content []byte) string {
+	if len(content) == 0 {
+		return name
+	}
+	return name + ": " + string(content)
+}
@@ -0,0 +1,623 @@
+// Package vfs simulates a filesystem as an in-memory tree of directories and files
+// with a current directory, as seen by a DOS-like shell.
+package vfs
+
+import (
+	"errors"
+	iofs "io/fs"
+	"strings"
+	"time"
+)
+
+// errors of the same kind as a standard io/fs error match it with errors.Is,
+// e.g. ErrSubdirDoesNotExist matches fs.ErrNotExist and ErrSubdirNotEmpty matches fs.ErrExist like syscall.ENOTEMPTY
+var (
+	ErrSubdirAlreadyExists  = newKindError("Subdirectory already exists", iofs.ErrExist)
+	ErrCannotMoveUpFromRoot = errors.New("Cannot move up from root directory")
+	ErrSubdirDoesNotExist   = newKindError("Subdirectory does not exist", iofs.ErrNotExist)
+	ErrInvalidPath          = newKindError("Invalid path", iofs.ErrInvalid)
+	ErrFileAlreadyExists    = newKindError("File already exists", iofs.ErrExist)
+	ErrFileDoesNotExist     = newKindError("File does not exist", iofs.ErrNotExist)
+	ErrSubdirNotEmpty       = newKindError("Subdirectory is not empty", iofs.ErrExist)
+	ErrSubdirInUse          = errors.New("Cannot remove current directory or its parent")
+	ErrCopyIntoItself       = newKindError("Cannot copy directory into itself", iofs.ErrInvalid)
+	ErrCopyOntoItself       = newKindError("Cannot copy file or directory onto itself", iofs.ErrInvalid)
+	ErrMoveIntoItself       = newKindError("Cannot move directory into itself", iofs.ErrInvalid)
+)
+
+// error of the simulator of the same kind as one of standard io/fs errors
+type kindError struct {
+	msg  string
+	kind error
+}
+
+func newKindError(msg string, kind error) error {
+	return &kindError{msg: msg, kind: kind}
+}
+
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// directory of the simulated filesystem
+type Dir struct {
+	name    string
+	parent  *Dir
+	subs    children[*Dir]
+	files   children[*File]
+	modTime time.Time
+}
+
+// regular file of the simulated filesystem
+type File struct {
+	name    string
+	parent  *Dir
+	content []byte
+	modTime time.Time
+}
+
+// tree of directories and files with the current directory
+type Filesystem struct {
+	current *Dir
+	root    *Dir
+	// returns time of changes, time.Now by default
+	clock func() time.Time
+}
+
+// creates represenation of the filesystem as a file tree
+// only creates root directory
+func New() *Filesystem {
+	root := Dir{
+		name:    "root",
+		modTime: time.Now(),
+	}
+	return &Filesystem{
+		current: &root,
+		root:    &root,
+		clock:   time.Now,
+	}
+}
+
+// sets function returning time of later changes, e.g. a fixed time in tests
+func (fs *Filesystem) SetClock(clock func() time.Time) {
+	fs.clock = clock
+}
+
+func (fs *Filesystem) now() time.Time {
+	return fs.clock()
+}
+
+func (fs *Filesystem) Root() *Dir {
+	return fs.root
+}
+
+func (fs *Filesystem) Current() *Dir {
+	return fs.current
+}
+
+func (d *Dir) Name() string {
+	return d.name
+}
+
+// returns nil for root
+func (d *Dir) Parent() *Dir {
+	return d.parent
+}
+
+// returns subdirectories sorted by name, the slice must not be modified
+func (d *Dir) Subdirs() []*Dir {
+	return d.subs.list()
+}
+
+// returns files sorted by name, the slice must not be modified
+func (d *Dir) Files() []*File {
+	return d.files.list()
+}
+
+// time when the directory was created, directories keep it when their content changes, as on FAT
+// zero time if it is not known, e.g. for directories loaded from state saved without times
+func (d *Dir) ModTime() time.Time {
+	return d.modTime
+}
+
+func (f *File) Name() string {
+	return f.name
+}
+
+func (f *File) Parent() *Dir {
+	return f.parent
+}
+
+// returns content of the file, it must not be modified
+func (f *File) Content() []byte {
+	return f.content
+}
+
+func (f *File) Size() int {
+	return len(f.content)
+}
+
+// time of the last change of the content of the file
+func (f *File) ModTime() time.Time {
+	return f.modTime
+}
+
+// adds subdirectory with given path
+// creates missing intermediate directories, nothing is created unless the whole path is valid
+// returns error if subdirectory or file with the same name already exists
+// or path is not valid
+func (fs *Filesystem) AddSubdir(path string) error {
+	current, steps, err := fs.splitPath(path)
+	if err != nil {
+		return err
+	}
+
+	// names of directories to create below the deepest existing directory on the path
+	missing := []string{}
+	for _, step := range steps {
+		if len(missing) == 0 {
+			next, err := walkStep(current, step)
+			if err == nil {
+				current = next
+				continue
+			}
+			// .. above the root is missing the same way as in cd, it cannot be created
+			if !errors.Is(err, ErrSubdirDoesNotExist) || step == ".." {
+				return err
+			}
+		}
+		switch {
+		case step == "." && len(missing) > 0:
+			continue
+		case step == ".." && len(missing) > 0:
+			missing = missing[:len(missing)-1]
+			continue
+		case !isValidNewName(step):
+			return ErrInvalidPath
+		}
+		if len(missing) == 0 {
+			if err := current.checkNameFree(step); err != nil {
+				return err
+			}
+		}
+		missing = append(missing, step)
+	}
+	if len(missing) == 0 {
+		return ErrSubdirAlreadyExists
+	}
+
+	for _, name := range missing {
+		next := &Dir{name: name, modTime: fs.now()}
+		current.addSubdir(next)
+		current = next
+	}
+	return nil
+}
+
+// creates empty file with given path
+// does nothing if the file already exists
+// returns error if subdirectory with the same name exists or path is not valid
+func (fs *Filesystem) Touch(path string) error {
+	parent, fileName, err := fs.resolveParent(path)
+	if err != nil {
+		return err
+	}
+	if !isValidNewName(fileName) {
+		return ErrInvalidPath
+	}
+	if parent.findFile(fileName) != nil {
+		return nil
+	}
+	if err := parent.checkNameFree(fileName); err != nil {
+		return err
+	}
+	parent.addFile(&File{name: fileName, modTime: fs.now()})
+	return nil
+}
+
+// appends data to the file with given path
+// creates the file if it doesn't exist
+func (fs *Filesystem) AppendFile(path string, data []byte) error {
+	if err := fs.Touch(path); err != nil {
+		return err
+	}
+	f, err := fs.ResolveFile(path)
+	if err != nil {
+		return err
+	}
+	f.content = append(f.content, data...)
+	f.modTime = fs.now()
+	return nil
+}
+
+// removes file with given path
+// returns error if the file doesn't exist
+func (fs *Filesystem) RemoveFile(path string) error {
+	f, err := fs.ResolveFile(path)
+	if err != nil {
+		return err
+	}
+	f.parent.removeFile(f)
+	return nil
+}
+
+// removes directory with given path, with all its content if recursive is set
+// returns error if directory is not empty and recursive is not set
+// or if the directory is the current directory or its parent
+func (fs *Filesystem) Rmdir(path string, recursive bool) error {
+	target, err := fs.ResolveDir(path)
+	if err != nil {
+		return err
+	}
+	return fs.removeDir(target, recursive)
+}
+
+// removes the directory, with all its content if recursive is set
+func (fs *Filesystem) removeDir(target *Dir, recursive bool) error {
+	if target.contains(fs.current) {
+		return ErrSubdirInUse
+	}
+	if !recursive && (target.subs.len() > 0 || target.files.len() > 0) {
+		return ErrSubdirNotEmpty
+	}
+	target.parent.removeSubdir(target)
+	return nil
+}
+
+// moves one directory upword
+// returns error if moving up is impossible
+func (fs *Filesystem) Up() error {
+	if fs.current == fs.root {
+		return ErrCannotMoveUpFromRoot
+	}
+	fs.current = fs.current.parent
+	return nil
+}
+
+// changes directory to given path
+// returns error if any directory on the path doesn't exist
+func (fs *Filesystem) Cd(path string) error {
+	destination, err := fs.ResolveDir(path)
+	if err != nil {
+		return err
+	}
+	fs.current = destination
+	return nil
+}
+
+// moves given subdirectory to destination
+// creates destination if it doesn't exist
+// returns error if moving is impossible
+func (fs *Filesystem) Mv(from, to string) error {
+	parent, name, err := fs.resolveParent(from)
+	if err != nil {
+		return err
+	}
+	dirToMove := parent.findSub(name)
+	if dirToMove == nil {
+		return ErrSubdirDoesNotExist
+	}
+
+	destination, err := fs.ResolveDir(to)
+	if errors.Is(err, ErrSubdirDoesNotExist) {
+		// last step of the path doesn't exist, it is the new name
+		newParent, newName, parentErr := fs.resolveParent(to)
+		if parentErr != nil {
+			return parentErr
+		}
+		if newName == "." || newName == ".." {
+			return err
+		}
+		if !isValidNewName(newName) {
+			return ErrInvalidPath
+		}
+		if dirToMove.contains(newParent) {
+			return ErrMoveIntoItself
+		}
+		if err := newParent.checkNameFree(newName); err != nil {
+			return err
+		}
+		moveDirectory(dirToMove, newParent, newName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// mv to the same dir without changing name == do nothing
+	if destination == dirToMove || destination == dirToMove.parent {
+		return nil
+	}
+	if dirToMove.contains(destination) {
+		return ErrMoveIntoItself
+	}
+
+	// check if directory name already exists in destination
+	if err := destination.checkNameFree(dirToMove.name); err != nil {
+		return err
+	}
+
+	moveDirectory(dirToMove, destination, dirToMove.name)
+	return nil
+}
+
+// resolves path to a directory
+// path starting with "\" or with the name of the root directory is absolute,
+// otherwise it is relative to the current directory
+// returns error if any directory on the path doesn't exist
+func (fs *Filesystem) ResolveDir(path string) (*Dir, error) {
+	current, steps, err := fs.splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return walkPath(current, steps)
+}
+
+// walks path components from the directory
+func walkPath(current *Dir, steps []string) (*Dir, error) {
+	var err error
+	for _, step := range steps {
+		current, err = walkStep(current, step)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+// resolves all but the last component of the path
+// returns directory containing the last component and its name
+func (fs *Filesystem) resolveParent(path string) (*Dir, string, error) {
+	current, steps, err := fs.splitPath(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(steps) == 0 {
+		// root has no parent
+		return nil, "", ErrInvalidPath
+	}
+	for _, step := range steps[:len(steps)-1] {
+		current, err = walkStep(current, step)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return current, steps[len(steps)-1], nil
+}
+
+// resolves path to a file, the same way as ResolveDir resolves directories
+// returns error if the file or any directory on the path doesn't exist
+func (fs *Filesystem) ResolveFile(path string) (*File, error) {
+	parent, name, err := fs.resolveParent(path)
+	if err != nil {
+		return nil, err
+	}
+	f := parent.findFile(name)
+	if f == nil {
+		return nil, ErrFileDoesNotExist
+	}
+	return f, nil
+}
+
+// returns directory where the path starts and its components
+// path starting with the name of the root is absolute, as paths written by Dir.Path are,
+// so the name alone always refers to the root, entry with that name is reached relatively, e.g. .\root
+func (fs *Filesystem) splitPath(path string) (*Dir, []string, error) {
+	if path == "" {
+		return nil, nil, ErrInvalidPath
+	}
+	start := fs.current
+	if strings.HasPrefix(path, "\\") {
+		start = fs.root
+		path = path[1:]
+	}
+	path = strings.TrimSuffix(path, "\\")
+	if path == "" {
+		if start != fs.root {
+			return nil, nil, ErrInvalidPath
+		}
+		return start, nil, nil
+	}
+
+	steps := strings.Split(path, "\\")
+	if start == fs.current && steps[0] == fs.root.name {
+		start = fs.root
+		steps = steps[1:]
+	}
+	return start, steps, nil
+}
+
+// moves from the directory by one path component
+func walkStep(current *Dir, step string) (*Dir, error) {
+	switch step {
+	case "":
+		return nil, ErrInvalidPath
+	case ".":
+		return current, nil
+	case "..":
+		if current.parent == nil {
+			return nil, ErrSubdirDoesNotExist
+		}
+		return current.parent, nil
+	}
+	sub := current.findSub(step)
+	if sub == nil {
+		return nil, ErrSubdirDoesNotExist
+	}
+	return sub, nil
+}
+
+// copies file or directory with all its content to destination
+// if destination is existing directory, copy keeps the name of the source
+// otherwise last component of destination is the name of the copy
+// existing entry with the same name is replaced only if overwrite is set
+// copies keep modification times of the originals
+// returns error if copying is impossible
+func (fs *Filesystem) Copy(from, to string, overwrite bool) error {
+	parent, name, err := fs.resolveParent(from)
+	if err != nil {
+		return err
+	}
+	srcDir := parent.findSub(name)
+	srcFile := parent.findFile(name)
+	if srcDir == nil && srcFile == nil {
+		return ErrFileDoesNotExist
+	}
+
+	destination, err := fs.ResolveDir(to)
+	if errors.Is(err, ErrSubdirDoesNotExist) {
+		// last step of the path doesn't exist as directory, it is the new name
+		var parentErr error
+		destination, name, parentErr = fs.resolveParent(to)
+		if parentErr != nil {
+			return parentErr
+		}
+		if name == "." || name == ".." {
+			return err
+		}
+		if !isValidNewName(name) {
+			return ErrInvalidPath
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if srcDir != nil && srcDir.contains(destination) {
+		return ErrCopyIntoItself
+	}
+	existingDir := destination.findSub(name)
+	existingFile := destination.findFile(name)
+	if (srcFile != nil && existingFile == srcFile) || (srcDir != nil && existingDir == srcDir) {
+		return ErrCopyOntoItself
+	}
+	// only entry of the same kind can be replaced
+	canReplace := overwrite && (srcDir == nil || existingFile == nil) && (srcFile == nil || existingDir == nil)
+	if !canReplace {
+		if err := destination.checkNameFree(name); err != nil {
+			return err
+		}
+	}
+	if existingDir != nil && existingDir.contains(fs.current) {
+		return ErrSubdirInUse
+	}
+
+	if srcFile != nil {
+		if existingFile != nil {
+			destination.removeFile(existingFile)
+		}
+		destination.addFile(srcFile.copy(name))
+		return nil
+	}
+	// copy is made before the replaced directory is removed, as it may contain the source
+	copied := srcDir.copy(name)
+	if existingDir != nil {
+		destination.removeSubdir(existingDir)
+	}
+	destination.addSubdir(copied)
+	return nil
+}
+
+// moves the directory to destination under given name
+func moveDirectory(dirToMove *Dir, destination *Dir, name string) {
+	dirToMove.parent.removeSubdir(dirToMove)
+	dirToMove.name = name
+	destination.addSubdir(dirToMove)
+}
+
+// path of the directory starting from root, e.g. root\sub1
+func (d *Dir) Path() string {
+	path := d.name
+	for current := d.parent; current != nil; current = current.parent {
+		path = current.name + "\\" + path
+	}
+	return path
+}
+
+func (d *Dir) findSub(name string) *Dir {
+	sub, _ := d.subs.get(name)
+	return sub
+}
+
+func (d *Dir) findFile(name string) *File {
+	f, _ := d.files.get(name)
+	return f
+}
+
+// subdirectories and files of a directory share the same namespace
+func (d *Dir) checkNameFree(name string) error {
+	if d.findSub(name) != nil {
+		return ErrSubdirAlreadyExists
+	}
+	if d.findFile(name) != nil {
+		return ErrFileAlreadyExists
+	}
+	return nil
+}
+
+func (d *Dir) addSubdir(sub *Dir) {
+	sub.parent = d
+	d.subs.add(sub)
+}
+
+func (d *Dir) removeSubdir(sub *Dir) {
+	d.subs.remove(sub)
+}
+
+func (d *Dir) removeFile(f *File) {
+	d.files.remove(f)
+}
+
+// returns deep copy of the directory with given name and without parent
+func (d *Dir) copy(name string) *Dir {
+	copied := &Dir{name: name, modTime: d.modTime}
+	for _, f := range d.files.byName {
+		copied.addFile(f.copy(f.name))
+	}
+	for _, subdir := range d.subs.byName {
+		copied.addSubdir(subdir.copy(subdir.name))
+	}
+	return copied
+}
+
+// returns copy of the file with given name and without parent
+func (f *File) copy(name string) *File {
+	return &File{
+		name:    name,
+		content: append([]byte(nil), f.content...),
+		modTime: f.modTime,
+	}
+}
+
+// checks if other directory is this directory or any of its descendants
+func (d *Dir) contains(other *Dir) bool {
+	for current := other; current != nil; current = current.parent {
+		if current == d {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Dir) addFile(f *File) {
+	f.parent = d
+	d.files.add(f)
+}
+
+// name of directory or file cannot be empty, cannot be special "." or ".."
+// and cannot contain path separators
+func isValidName(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	return !strings.ContainsAny(name, "\\/")
+}
+
+// entries created or renamed by commands cannot have wildcards in names either,
+// so that patterns stay unambiguous, imported and loaded entries may have them
+func isValidNewName(name string) bool {
+	return isValidName(name) && !HasWildcards(name)
+}
@@ -0,0 +1,210 @@
+package vfs
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// names are drawn from a small pool, so that generated paths often hit existing directories
+var propertyNames = []string{"a", "b", "c", "d", ".", ".."}
+
+func randomPath(r *rand.Rand) string {
+	steps := make([]string, 1+r.Intn(3))
+	for i := range steps {
+		steps[i] = propertyNames[r.Intn(len(propertyNames))]
+	}
+	path := strings.Join(steps, "\\")
+	if r.Intn(5) == 0 {
+		path = "\\" + path
+	}
+	return path
+}
+
+// applies random mkdir, mv, cd or up to the filesystem, errors are expected and ignored
+func applyRandomOperation(r *rand.Rand, fs *Filesystem) {
+	switch r.Intn(4) {
+	case 0:
+		fs.AddSubdir(randomPath(r))
+	case 1:
+		fs.Mv(randomPath(r), randomPath(r))
+	case 2:
+		fs.Cd(randomPath(r))
+	case 3:
+		fs.Up()
+	}
+}
+
+// walks the tree from root and checks its invariants
+// returns all reachable directories
+func checkTreeInvariants(t *testing.T, fs *Filesystem) map[*Dir]bool {
+	t.Helper()
+	reachable := map[*Dir]bool{}
+	var walk func(d *Dir)
+	walk = func(d *Dir) {
+		if reachable[d] {
+			t.Fatalf("directory %s reachable more than once", d.Path())
+		}
+		reachable[d] = true
+		if len(d.Subdirs()) != d.subs.len() {
+			t.Fatalf("sorted subdirectories of %s don't match the index", d.Path())
+		}
+		for i, sub := range d.Subdirs() {
+			if sub.parent != d {
+				t.Fatalf("parent of %s is not %s", sub.name, d.Path())
+			}
+			if i > 0 && d.Subdirs()[i-1].name >= sub.name {
+				t.Fatalf("subdirectories of %s not sorted or not unique", d.Path())
+			}
+			if d.findSub(sub.name) != sub {
+				t.Fatalf("subdirectory %s not indexed by its name", sub.name)
+			}
+			walk(sub)
+		}
+	}
+	if fs.root.parent != nil {
+		t.Fatalf("root has parent")
+	}
+	walk(fs.root)
+	if !reachable[fs.current] {
+		t.Fatalf("current directory %s not reachable from root", fs.current.name)
+	}
+	return reachable
+}
+
+func TestRandomOperationsKeepTreeInvariants(t *testing.T) {
+	t.Parallel()
+	property := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		fs := New()
+		seen := map[*Dir]bool{}
+		for i := 0; i < 200; i++ {
+			applyRandomOperation(r, fs)
+			reachable := checkTreeInvariants(t, fs)
+			// none of the operations removes directories
+			for d := range seen {
+				if !reachable[d] {
+					t.Logf("seed %d: directory %s detached from the tree", seed, d.name)
+					return false
+				}
+			}
+			seen = reachable
+		}
+		return true
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 300}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lists paths of all directories and files below root, directories end with a slash
+func listTree(fs *Filesystem) []string {
+	paths := []string{}
+	fs.walk(func(path string, d *Dir, f *File) error {
+		if d != nil {
+			path += "/"
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	return paths
+}
+
+func TestModTime(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2023, 5, 1, 14, 5, 0, 0, time.UTC)
+	now := created
+	fs := New()
+	fs.SetClock(func() time.Time { return now })
+	fs.AddSubdir("sub1")
+	fs.Touch("sub1\\a.txt")
+	fs.Touch("b.txt")
+
+	now = created.Add(time.Hour)
+	fs.AppendFile("sub1\\a.txt", []byte("changed"))
+	fs.Copy("sub1", "sub2", false)
+	f, _ := fs.OpenFile("b.txt", os.O_WRONLY, 0)
+	f.Write([]byte("written"))
+	f.Close()
+
+	expected := map[string]time.Time{
+		"sub1":       created,
+		"sub1/a.txt": now,
+		"sub2":       created,
+		"sub2/a.txt": now,
+		"b.txt":      now,
+	}
+	for name, modTime := range expected {
+		info, err := fs.Stat(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !info.ModTime().Equal(modTime) {
+			t.Fatalf("modification time of %s mismatch:\nwant: %v\ngot: %v", name, modTime, info.ModTime())
+		}
+	}
+}
+
+func TestCdToPath(t *testing.T) {
+	t.Parallel()
+	fs := New()
+	fs.AddSubdir("a")
+	fs.AddSubdir(".\\root\\a")
+	fs.AddSubdir(".\\root\\root")
+	dirs := []*Dir{}
+	var walk func(d *Dir)
+	walk = func(d *Dir) {
+		dirs = append(dirs, d)
+		for _, subdir := range d.Subdirs() {
+			walk(subdir)
+		}
+	}
+	walk(fs.Root())
+
+	// path of every directory leads back to it from every directory
+	for _, from := range dirs {
+		for _, to := range dirs {
+			fs.current = from
+			if err := fs.Cd(to.Path()); err != nil {
+				t.Fatalf("cd from %s to %s: unexpected error: %v", from.Path(), to.Path(), err)
+			}
+			if fs.Current() != to {
+				t.Fatalf("cd from %s to %s ended in %s", from.Path(), to.Path(), fs.Current().Path())
+			}
+		}
+	}
+}
+
+func TestRootNameRefersToRoot(t *testing.T) {
+	t.Parallel()
+	fs := New()
+	fs.AddSubdir("a\\root")
+	fs.Cd("a")
+
+	if err := fs.AddSubdir("root"); !errors.Is(err, ErrSubdirAlreadyExists) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", ErrSubdirAlreadyExists, err)
+	}
+	if dir, err := fs.ResolveDir("root"); err != nil || dir != fs.Root() {
+		t.Fatalf("root resolved to %v, %v", dir, err)
+	}
+	if err := fs.Touch("root"); !errors.Is(err, ErrInvalidPath) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", ErrInvalidPath, err)
+	}
+	if err := fs.Touch("root\\f"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fs.Touch(".\\root\\g"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"f", "a/", "a/root/", "a/root/g"}
+	if diff := cmp.Diff(want, listTree(fs)); diff != "" {
+		t.Fatalf("tree mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,124 @@
+package vfs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// decides how directory from disk is imported
+type ImportOptions struct {
+	// follow symbolic links, otherwise they are skipped
+	FollowSymlinks bool
+	// skip entries that cannot be read because of missing permissions, otherwise import fails
+	SkipDenied bool
+	// maximal depth of imported entries, entries of the imported directory have depth 1
+	// negative value means no limit
+	MaxDepth int
+	// read content of the files, otherwise files are imported empty
+	WithContent bool
+}
+
+// creates filesystem with the content of given directory on disk as content of root
+// the directory on disk is only read
+// returns error if the directory cannot be read or contains names not valid in the simulator
+func Import(path string, opts ImportOptions) (*Filesystem, error) {
+	fs := New()
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, err
+	}
+	visited := map[string]bool{realPath: true}
+	if err := importDir(fs.root, path, 0, opts, visited); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// adds entries of directory on disk to the simulated directory
+// visited contains real paths of directories being imported, to break symbolic link cycles
+func importDir(d *Dir, path string, depth int, opts ImportOptions, visited map[string]bool) error {
+	if opts.MaxDepth >= 0 && depth >= opts.MaxDepth {
+		return nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if opts.SkipDenied && errors.Is(err, os.ErrPermission) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		entryPath := filepath.Join(path, entry.Name())
+		var info os.FileInfo
+		if entry.Type()&os.ModeSymlink != 0 {
+			if !opts.FollowSymlinks {
+				continue
+			}
+			info, err = os.Stat(entryPath)
+		} else {
+			info, err = entry.Info()
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			// dangling link or entry removed during import has nothing to import
+			continue
+		}
+		if err != nil {
+			if opts.SkipDenied && errors.Is(err, os.ErrPermission) {
+				continue
+			}
+			return err
+		}
+		mode := info.Mode().Type()
+		if !mode.IsDir() && !mode.IsRegular() {
+			// devices, sockets, pipes etc. are not simulated
+			continue
+		}
+		if !isValidName(entry.Name()) {
+			return fmt.Errorf("%w: %s", ErrInvalidPath, entryPath)
+		}
+
+		if mode.IsRegular() {
+			if err := importFile(d, entryPath, info, opts); err != nil {
+				return err
+			}
+			continue
+		}
+
+		realPath, err := filepath.EvalSymlinks(entryPath)
+		if err != nil {
+			return err
+		}
+		if visited[realPath] {
+			// symbolic link to the directory being imported
+			continue
+		}
+		subdir := &Dir{name: entry.Name(), modTime: info.ModTime()}
+		d.addSubdir(subdir)
+		visited[realPath] = true
+		err = importDir(subdir, entryPath, depth+1, opts, visited)
+		delete(visited, realPath)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func importFile(d *Dir, path string, info os.FileInfo, opts ImportOptions) error {
+	f := &File{name: info.Name(), modTime: info.ModTime()}
+	if opts.WithContent {
+		content, err := os.ReadFile(path)
+		if opts.SkipDenied && errors.Is(err, os.ErrPermission) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		f.content = content
+	}
+	d.addFile(f)
+	return nil
+}
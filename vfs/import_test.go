@@ -0,0 +1,187 @@
+package vfs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func mustMkdir(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustWriteFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportDir(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		opts         ImportOptions
+		expectedTree []string
+	}{
+		{
+			name: "symbolic links skipped",
+			opts: ImportOptions{MaxDepth: -1},
+			expectedTree: []string{
+				"a.txt",
+				"sub1/",
+				"sub1/sub2/",
+				"sub1/sub2/b.txt",
+			},
+		},
+		{
+			name: "symbolic links followed without cycles",
+			opts: ImportOptions{MaxDepth: -1, FollowSymlinks: true},
+			expectedTree: []string{
+				"a.txt",
+				"link/",
+				"link/sub2/",
+				"link/sub2/b.txt",
+				"sub1/",
+				"sub1/sub2/",
+				"sub1/sub2/b.txt",
+			},
+		},
+		{
+			name: "depth limit",
+			opts: ImportOptions{MaxDepth: 1},
+			expectedTree: []string{
+				"a.txt",
+				"sub1/",
+			},
+		},
+		{
+			name:         "zero depth",
+			opts:         ImportOptions{MaxDepth: 0},
+			expectedTree: []string{},
+		},
+	}
+
+	// tree on disk:
+	//
+	//	base
+	//	├── a.txt (content "hello\n")
+	//	├── link -> sub1
+	//	└── sub1
+	//	    ├── loop -> ..
+	//	    └── sub2
+	//	        └── b.txt
+	base := t.TempDir()
+	mustMkdir(t, filepath.Join(base, "sub1", "sub2"))
+	mustWriteFile(t, filepath.Join(base, "a.txt"), "hello\n")
+	mustWriteFile(t, filepath.Join(base, "sub1", "sub2", "b.txt"), "")
+	if err := os.Symlink("sub1", filepath.Join(base, "link")); err != nil {
+		t.Skipf("symbolic links not supported: %v", err)
+	}
+	if err := os.Symlink("..", filepath.Join(base, "sub1", "loop")); err != nil {
+		t.Skipf("symbolic links not supported: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, err := Import(base, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expectedTree, listTree(fs)); diff != "" {
+				t.Fatalf("tree mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestImportDirContent(t *testing.T) {
+	t.Parallel()
+	base := t.TempDir()
+	mustWriteFile(t, filepath.Join(base, "a.txt"), "hello\n")
+	for _, WithContent := range []bool{false, true} {
+		fs, err := Import(base, ImportOptions{MaxDepth: -1, WithContent: WithContent})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		content, err := fs.ReadFile("a.txt")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := ""
+		if WithContent {
+			expected = "hello\n"
+		}
+		if string(content) != expected {
+			t.Fatalf("content mismatch:\nwant: %q\ngot: %q", expected, content)
+		}
+	}
+}
+
+func TestImportDirPermissionDenied(t *testing.T) {
+	t.Parallel()
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+	base := t.TempDir()
+	mustMkdir(t, filepath.Join(base, "denied", "sub1"))
+	mustMkdir(t, filepath.Join(base, "sub2"))
+	if err := os.Chmod(filepath.Join(base, "denied"), 0); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chmod(filepath.Join(base, "denied"), 0755)
+	})
+
+	if _, err := Import(base, ImportOptions{MaxDepth: -1}); !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", os.ErrPermission, err)
+	}
+
+	fs, err := Import(base, ImportOptions{MaxDepth: -1, SkipDenied: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedTree := []string{
+		"denied/",
+		"sub2/",
+	}
+	if diff := cmp.Diff(expectedTree, listTree(fs)); diff != "" {
+		t.Fatalf("tree mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestImportDirInvalidName(t *testing.T) {
+	t.Parallel()
+	base := t.TempDir()
+	if err := os.Mkdir(filepath.Join(base, "back\\slash"), 0755); err != nil {
+		t.Skipf("cannot create name with backslash: %v", err)
+	}
+	if _, err := Import(base, ImportOptions{MaxDepth: -1}); !errors.Is(err, ErrInvalidPath) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", ErrInvalidPath, err)
+	}
+}
+
+func TestImportDirWildcardName(t *testing.T) {
+	t.Parallel()
+	base := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "what?.txt"), nil, 0644); err != nil {
+		t.Skipf("cannot create name with wildcard: %v", err)
+	}
+	mustMkdir(t, filepath.Join(base, "sub*"))
+	fs, err := Import(base, ImportOptions{MaxDepth: -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedTree := []string{
+		"what?.txt",
+		"sub*/",
+	}
+	if diff := cmp.Diff(expectedTree, listTree(fs)); diff != "" {
+		t.Fatalf("tree mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,229 @@
+package vfs
+
+import (
+	"bytes"
+	"io"
+	iofs "io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// Open, ReadDir, ReadFile, Stat and Sub implement io/fs interfaces
+// names are separated by slashes and relative to root, regardless of the current directory
+
+func (fs *Filesystem) Open(name string) (iofs.File, error) {
+	return dirFS{root: fs.root}.Open(name)
+}
+
+func (fs *Filesystem) ReadDir(name string) ([]iofs.DirEntry, error) {
+	return dirFS{root: fs.root}.ReadDir(name)
+}
+
+func (fs *Filesystem) ReadFile(name string) ([]byte, error) {
+	return dirFS{root: fs.root}.ReadFile(name)
+}
+
+func (fs *Filesystem) Stat(name string) (iofs.FileInfo, error) {
+	return dirFS{root: fs.root}.Stat(name)
+}
+
+func (fs *Filesystem) Sub(dir string) (iofs.FS, error) {
+	return dirFS{root: fs.root}.Sub(dir)
+}
+
+// io/fs view of the directory, names are relative to it
+type dirFS struct {
+	root *Dir
+}
+
+func (d dirFS) Open(name string) (iofs.File, error) {
+	info, err := d.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.dir != nil {
+		return &openDir{info: info, entries: dirEntries(info.dir)}, nil
+	}
+	return &openFile{info: info, Reader: bytes.NewReader(info.file.content)}, nil
+}
+
+// returns entries of the directory sorted by name
+func (d dirFS) ReadDir(name string) ([]iofs.DirEntry, error) {
+	info, err := d.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.dir == nil {
+		return nil, &iofs.PathError{Op: "readdir", Path: name, Err: iofs.ErrInvalid}
+	}
+	return dirEntries(info.dir), nil
+}
+
+// returns copy of the content of the file
+func (d dirFS) ReadFile(name string) ([]byte, error) {
+	info, err := d.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.file == nil {
+		return nil, &iofs.PathError{Op: "read", Path: name, Err: iofs.ErrInvalid}
+	}
+	return bytes.Clone(info.file.content), nil
+}
+
+func (d dirFS) Stat(name string) (iofs.FileInfo, error) {
+	info, err := d.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// returns view of the subdirectory, it reflects later changes of the filesystem
+func (d dirFS) Sub(dir string) (iofs.FS, error) {
+	info, err := d.lookup("sub", dir)
+	if err != nil {
+		return nil, err
+	}
+	if info.dir == nil {
+		return nil, &iofs.PathError{Op: "sub", Path: dir, Err: iofs.ErrInvalid}
+	}
+	return dirFS{root: info.dir}, nil
+}
+
+// finds directory or file with given name
+// returns *fs.PathError with op if the name is not valid or doesn't exist
+func (d dirFS) lookup(op, name string) (fileInfo, error) {
+	if !iofs.ValidPath(name) {
+		return fileInfo{}, &iofs.PathError{Op: op, Path: name, Err: iofs.ErrInvalid}
+	}
+	if name == "." {
+		return fileInfo{name: name, dir: d.root}, nil
+	}
+	current := d.root
+	steps := strings.Split(name, "/")
+	for i, step := range steps {
+		if sub := current.findSub(step); sub != nil {
+			current = sub
+			continue
+		}
+		if f := current.findFile(step); f != nil && i == len(steps)-1 {
+			return fileInfo{name: f.name, file: f}, nil
+		}
+		return fileInfo{}, &iofs.PathError{Op: op, Path: name, Err: iofs.ErrNotExist}
+	}
+	return fileInfo{name: path.Base(name), dir: current}, nil
+}
+
+// subdirectories and files merged into a single list sorted by name
+func dirEntries(d *Dir) []iofs.DirEntry {
+	subs, files := d.Subdirs(), d.Files()
+	entries := make([]iofs.DirEntry, 0, len(subs)+len(files))
+	for len(subs) > 0 || len(files) > 0 {
+		if len(files) == 0 || (len(subs) > 0 && subs[0].name < files[0].name) {
+			entries = append(entries, fileInfo{name: subs[0].name, dir: subs[0]})
+			subs = subs[1:]
+			continue
+		}
+		entries = append(entries, fileInfo{name: files[0].name, file: files[0]})
+		files = files[1:]
+	}
+	return entries
+}
+
+// describes directory or file, implements both fs.FileInfo and fs.DirEntry
+// exactly one of dir and file is set
+type fileInfo struct {
+	name string
+	dir  *Dir
+	file *File
+}
+
+func (i fileInfo) Name() string {
+	return i.name
+}
+
+func (i fileInfo) Size() int64 {
+	if i.file == nil {
+		return 0
+	}
+	return int64(len(i.file.content))
+}
+
+func (i fileInfo) Mode() iofs.FileMode {
+	if i.dir != nil {
+		return iofs.ModeDir | 0755
+	}
+	return 0644
+}
+
+func (i fileInfo) ModTime() time.Time {
+	if i.dir != nil {
+		return i.dir.modTime
+	}
+	return i.file.modTime
+}
+
+func (i fileInfo) IsDir() bool {
+	return i.dir != nil
+}
+
+func (i fileInfo) Sys() any {
+	return nil
+}
+
+func (i fileInfo) Type() iofs.FileMode {
+	return i.Mode().Type()
+}
+
+func (i fileInfo) Info() (iofs.FileInfo, error) {
+	return i, nil
+}
+
+// opened directory, entries are read from the snapshot taken when it was opened
+type openDir struct {
+	info    fileInfo
+	entries []iofs.DirEntry
+	offset  int
+}
+
+func (d *openDir) Stat() (iofs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *openDir) Read([]byte) (int, error) {
+	return 0, &iofs.PathError{Op: "read", Path: d.info.name, Err: iofs.ErrInvalid}
+}
+
+func (d *openDir) Close() error {
+	return nil
+}
+
+// returns next n entries, all remaining entries if n <= 0
+func (d *openDir) ReadDir(n int) ([]iofs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n > 0 && len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if n > 0 && n < len(remaining) {
+		remaining = remaining[:n]
+	}
+	d.offset += len(remaining)
+	return remaining, nil
+}
+
+// opened file, supports also io.Seeker and io.ReaderAt
+// content is read from the snapshot taken when it was opened
+type openFile struct {
+	info fileInfo
+	*bytes.Reader
+}
+
+func (f *openFile) Stat() (iofs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *openFile) Close() error {
+	return nil
+}
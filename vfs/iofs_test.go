@@ -0,0 +1,169 @@
+package vfs
+
+import (
+	"errors"
+	"io"
+	iofs "io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	_ iofs.ReadDirFS  = (*Filesystem)(nil)
+	_ iofs.ReadFileFS = (*Filesystem)(nil)
+	_ iofs.StatFS     = (*Filesystem)(nil)
+	_ iofs.SubFS      = (*Filesystem)(nil)
+)
+
+func createFSFixture() *Filesystem {
+	fs := New()
+	fs.AddSubdir("sub1\\sub3")
+	fs.AddSubdir("sub2")
+	fs.AppendFile("a.txt", []byte("first line\n"))
+	fs.AppendFile("sub1\\b.txt", []byte("second line\n"))
+	fs.Touch("sub1\\sub3\\empty.txt")
+	// io/fs names are relative to root, not to the current directory
+	fs.Cd("sub2")
+	return fs
+}
+
+func TestFS(t *testing.T) {
+	t.Parallel()
+	fs := createFSFixture()
+	if err := fstest.TestFS(fs, "a.txt", "sub1/b.txt", "sub1/sub3/empty.txt", "sub2"); err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := fs.Sub("sub1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fstest.TestFS(sub, "b.txt", "sub3/empty.txt"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFSWalkDir(t *testing.T) {
+	t.Parallel()
+	fs := createFSFixture()
+	paths := []string{}
+	err := iofs.WalkDir(fs, ".", func(path string, d iofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			path += "/"
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"./", "a.txt", "sub1/", "sub1/b.txt", "sub1/sub3/", "sub1/sub3/empty.txt", "sub2/"}
+	if diff := cmp.Diff(expected, paths); diff != "" {
+		t.Fatalf("paths mismatch (-want +got):\n%s", diff)
+	}
+
+	matches, err := iofs.Glob(fs, "sub1/*.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"sub1/b.txt"}, matches); diff != "" {
+		t.Fatalf("matches mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFSErrors(t *testing.T) {
+	t.Parallel()
+	fs := createFSFixture()
+	tests := []struct {
+		name        string
+		call        func() error
+		expectedErr error
+	}{
+		{
+			name: "open missing file",
+			call: func() error {
+				_, err := fs.Open("sub1/missing.txt")
+				return err
+			},
+			expectedErr: iofs.ErrNotExist,
+		},
+		{
+			name: "open path through file",
+			call: func() error {
+				_, err := fs.Open("a.txt/b.txt")
+				return err
+			},
+			expectedErr: iofs.ErrNotExist,
+		},
+		{
+			name: "open backslash path",
+			call: func() error {
+				_, err := fs.Open("\\sub1")
+				return err
+			},
+			expectedErr: iofs.ErrNotExist,
+		},
+		{
+			name: "open invalid path",
+			call: func() error {
+				_, err := fs.Open("sub1/../a.txt")
+				return err
+			},
+			expectedErr: iofs.ErrInvalid,
+		},
+		{
+			name: "read directory as file",
+			call: func() error {
+				_, err := fs.ReadFile("sub1")
+				return err
+			},
+			expectedErr: iofs.ErrInvalid,
+		},
+		{
+			name: "sub of file",
+			call: func() error {
+				_, err := fs.Sub("a.txt")
+				return err
+			},
+			expectedErr: iofs.ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var pathErr *iofs.PathError
+			if !errors.As(err, &pathErr) || !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestFSReadFileReturnsCopy(t *testing.T) {
+	t.Parallel()
+	fs := createFSFixture()
+	content, err := fs.ReadFile("a.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content[0] = 'F'
+
+	f, err := fs.Open("a.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	reread, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(reread) != "first line\n" {
+		t.Fatalf("content mismatch:\nwant: %q\ngot: %q", "first line\n", reread)
+	}
+}
@@ -0,0 +1,143 @@
+package vfs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidState = errors.New("Invalid filesystem state")
+)
+
+// serialized form of the filesystem
+type filesystemState struct {
+	// absolute path of the current directory, e.g. root\sub1
+	Current string   `json:"current"`
+	Root    dirState `json:"root"`
+}
+
+// modification times are missing in states saved by older versions, they are loaded as zero times
+type dirState struct {
+	Name    string      `json:"name"`
+	ModTime time.Time   `json:"modTime"`
+	Dirs    []dirState  `json:"dirs,omitempty"`
+	Files   []fileState `json:"files,omitempty"`
+}
+
+// content is encoded as base64, so binary files survive the round trip
+type fileState struct {
+	Name    string    `json:"name"`
+	ModTime time.Time `json:"modTime"`
+	Content []byte    `json:"content,omitempty"`
+}
+
+// writes whole tree and the current directory as JSON
+func (fs *Filesystem) SaveState(w io.Writer) error {
+	state := filesystemState{
+		Current: fs.current.Path(),
+		Root:    newDirState(fs.root),
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(state)
+}
+
+// creates filesystem from JSON written by SaveState
+// returns error if the state is malformed or doesn't describe valid tree
+func Load(r io.Reader) (*Filesystem, error) {
+	var state filesystemState
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidState, err)
+	}
+
+	if !isValidName(state.Root.Name) {
+		return nil, fmt.Errorf("%w: invalid root name %q", ErrInvalidState, state.Root.Name)
+	}
+	root := &Dir{name: state.Root.Name, modTime: state.Root.ModTime}
+	if err := loadDirState(root, state.Root); err != nil {
+		return nil, err
+	}
+	fs := &Filesystem{
+		current: root,
+		root:    root,
+		clock:   time.Now,
+	}
+
+	current, err := fs.resolveSavedPath(state.Current)
+	if err != nil {
+		return nil, fmt.Errorf("%w: current directory %q: %v", ErrInvalidState, state.Current, err)
+	}
+	fs.current = current
+	return fs, nil
+}
+
+// resolves path written by Dir.Path, it starts with the name of the root and is walked from the root
+func (fs *Filesystem) resolveSavedPath(path string) (*Dir, error) {
+	steps := strings.Split(path, "\\")
+	if steps[0] != fs.root.name {
+		return nil, ErrInvalidPath
+	}
+	current := fs.root
+	for _, step := range steps[1:] {
+		var err error
+		current, err = walkStep(current, step)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+func newDirState(d *Dir) dirState {
+	state := dirState{Name: d.name, ModTime: d.modTime}
+	for _, subdir := range d.Subdirs() {
+		state.Dirs = append(state.Dirs, newDirState(subdir))
+	}
+	for _, f := range d.Files() {
+		state.Files = append(state.Files, fileState{
+			Name:    f.name,
+			ModTime: f.modTime,
+			Content: f.content,
+		})
+	}
+	return state
+}
+
+// fills the directory with subdirectories and files described by the state
+func loadDirState(d *Dir, state dirState) error {
+	for _, subState := range state.Dirs {
+		if err := checkLoadedName(d, subState.Name); err != nil {
+			return err
+		}
+		subdir := &Dir{name: subState.Name, modTime: subState.ModTime}
+		d.addSubdir(subdir)
+		if err := loadDirState(subdir, subState); err != nil {
+			return err
+		}
+	}
+	for _, f := range state.Files {
+		if err := checkLoadedName(d, f.Name); err != nil {
+			return err
+		}
+		d.addFile(&File{
+			name:    f.Name,
+			content: f.Content,
+			modTime: f.ModTime,
+		})
+	}
+	return nil
+}
+
+func checkLoadedName(d *Dir, name string) error {
+	if !isValidName(name) {
+		return fmt.Errorf("%w: invalid name %q in %s", ErrInvalidState, name, d.Path())
+	}
+	if err := d.checkNameFree(name); err != nil {
+		return fmt.Errorf("%w: %v: %s\\%s", ErrInvalidState, err, d.Path(), name)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package vfs
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSaveAndLoadState(t *testing.T) {
+	t.Parallel()
+	modTime := time.Date(2023, 5, 1, 14, 5, 0, 0, time.UTC)
+	fs := New()
+	fs.SetClock(func() time.Time { return modTime })
+	fs.AddSubdir("sub1\\sub3\\sub4")
+	fs.AddSubdir("sub2")
+	fs.AppendFile("sub1\\file1.txt", []byte("first line\n"))
+	fs.Touch("sub2\\empty.txt")
+	fs.Cd("sub1\\sub3")
+
+	var buf bytes.Buffer
+	if err := fs.SaveState(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if loaded.current.Path() != fs.current.Path() {
+		t.Fatalf("current dir mismatch:\nwant: %s\ngot: %s", fs.current.Path(), loaded.current.Path())
+	}
+	if diff := cmp.Diff(listTree(fs), listTree(loaded)); diff != "" {
+		t.Fatalf("tree mismatch (-want +got):\n%s", diff)
+	}
+	content, err := loaded.ReadFile("sub1/file1.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "first line\n" {
+		t.Fatalf("content mismatch:\nwant: %q\ngot: %q", "first line\n", content)
+	}
+	for _, name := range []string{"sub1/sub3", "sub1/file1.txt"} {
+		info, err := loaded.Stat(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !info.ModTime().Equal(modTime) {
+			t.Fatalf("modification time of %s mismatch:\nwant: %v\ngot: %v", name, modTime, info.ModTime())
+		}
+	}
+}
+
+func TestSaveAndLoadStateWithDirNamedAsRoot(t *testing.T) {
+	t.Parallel()
+	for _, current := range []string{"a", ".\\root\\a"} {
+		fs := New()
+		fs.AddSubdir("a")
+		fs.AddSubdir(".\\root\\a")
+		fs.Cd(current)
+		var buf bytes.Buffer
+		if err := fs.SaveState(&buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		loaded, err := Load(&buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if loaded.current.Path() != fs.current.Path() {
+			t.Fatalf("current dir mismatch:\nwant: %s\ngot: %s", fs.current.Path(), loaded.current.Path())
+		}
+		if diff := cmp.Diff(listTree(fs), listTree(loaded)); diff != "" {
+			t.Fatalf("tree mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestSaveAndLoadStateWithBinaryContent(t *testing.T) {
+	t.Parallel()
+	content := []byte{0xff, 0xfe, 0x00, 0x80}
+	fs := New()
+	fs.AppendFile("binary.bin", content)
+
+	var buf bytes.Buffer
+	if err := fs.SaveState(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := loaded.ReadFile("binary.bin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("content mismatch:\nwant: % x\ngot: % x", content, got)
+	}
+}
+
+func TestLoadInvalidState(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		state string
+	}{
+		{
+			name:  "malformed json",
+			state: `{"current": "root", "root": {`,
+		},
+		{
+			name:  "missing root",
+			state: `{"current": "root"}`,
+		},
+		{
+			name:  "duplicated subdirectory",
+			state: `{"current": "root", "root": {"name": "root", "dirs": [{"name": "sub1"}, {"name": "sub1"}]}}`,
+		},
+		{
+			name:  "file with name of subdirectory",
+			state: `{"current": "root", "root": {"name": "root", "dirs": [{"name": "sub1"}], "files": [{"name": "sub1"}]}}`,
+		},
+		{
+			name:  "invalid name",
+			state: `{"current": "root", "root": {"name": "root", "dirs": [{"name": "sub1\\sub2"}]}}`,
+		},
+		{
+			name:  "current directory does not exist",
+			state: `{"current": "root\\sub1", "root": {"name": "root"}}`,
+		},
+		{
+			name:  "current directory relative to the root",
+			state: `{"current": "sub1", "root": {"name": "root", "dirs": [{"name": "sub1"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(strings.NewReader(tt.state))
+			if !errors.Is(err, ErrInvalidState) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", ErrInvalidState, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package vfs
+
+import (
+	iofs "io/fs"
+	"strings"
+)
+
+var (
+	ErrNoMatch = newKindError("No matching file or directory", iofs.ErrNotExist)
+)
+
+// reports whether the path contains DOS wildcards * or ?
+func HasWildcards(path string) bool {
+	return strings.ContainsAny(path, "*?")
+}
+
+// reports whether the name matches the DOS-style pattern,
+// * matches any sequence of characters, ? matches a single character, other characters match themselves
+func MatchWildcard(pattern, name string) bool {
+	p, n := []rune(pattern), []rune(name)
+	// position after the last * in the pattern and in the name where it started matching, for backtracking
+	star, starName := -1, 0
+	i, j := 0, 0
+	for j < len(n) {
+		switch {
+		case i < len(p) && p[i] == '*':
+			star, starName = i+1, j
+			i++
+		case i < len(p) && (p[i] == '?' || p[i] == n[j]):
+			i++
+			j++
+		case star >= 0:
+			// the last * takes one more character
+			starName++
+			i, j = star, starName
+		default:
+			return false
+		}
+	}
+	for i < len(p) && p[i] == '*' {
+		i++
+	}
+	return i == len(p)
+}
+
+// expands wildcards in the last component of the path to paths of matching subdirectories and files
+// sorted by name, e.g. sub* in root\sub1 and sub* in ..\sub1, path without wildcards is returned as it is
+// matches in the current directory start with .\, so that an entry named as the root stays relative
+// returns ErrNoMatch if nothing matches and ErrInvalidPath if other components contain wildcards
+func (fs *Filesystem) ExpandWildcards(path string) ([]string, error) {
+	if !HasWildcards(path) {
+		return []string{path}, nil
+	}
+	_, prefix, names, err := fs.matchPattern(path)
+	if err != nil {
+		return nil, err
+	}
+	if prefix == "" {
+		prefix = ".\\"
+	}
+	matches := make([]string, 0, len(names))
+	for _, name := range names {
+		matches = append(matches, prefix+name)
+	}
+	return matches, nil
+}
+
+// resolves directory of the path with wildcards in its last component and returns it with that component,
+// e.g. root\sub1 and sub* for ..\sub* in root\sub1\sub2, a trailing \ is ignored as in ExpandWildcards
+// returns ErrNoMatch if nothing matches and ErrInvalidPath if other components contain wildcards
+func (fs *Filesystem) ResolvePattern(path string) (*Dir, string, error) {
+	parent, _, _, err := fs.matchPattern(path)
+	if err != nil {
+		return nil, "", err
+	}
+	_, pattern := splitPattern(path)
+	return parent, pattern, nil
+}
+
+// returns directory of the pattern, path of the directory as written and names of matching entries sorted
+func (fs *Filesystem) matchPattern(path string) (*Dir, string, []string, error) {
+	prefix, pattern := splitPattern(path)
+	if HasWildcards(prefix) {
+		return nil, "", nil, ErrInvalidPath
+	}
+	parent, _, err := fs.resolveParent(prefix + pattern)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	names := []string{}
+	for _, entry := range dirEntries(parent) {
+		if MatchWildcard(pattern, entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) == 0 {
+		return nil, "", nil, ErrNoMatch
+	}
+	return parent, prefix, names, nil
+}
+
+// splits path into its directory, as written with the trailing \, and its last component,
+// e.g. ..\sub* into ..\ and sub*, directory is empty for the current one
+func splitPattern(path string) (string, string) {
+	path = strings.TrimSuffix(path, "\\")
+	i := strings.LastIndex(path, "\\")
+	return path[:i+1], path[i+1:]
+}
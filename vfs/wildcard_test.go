@@ -0,0 +1,112 @@
+package vfs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMatchWildcard(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{pattern: "sub*", name: "sub1", expected: true},
+		{pattern: "sub*", name: "sub", expected: true},
+		{pattern: "sub*", name: "a.sub", expected: false},
+		{pattern: "sub?", name: "sub1", expected: true},
+		{pattern: "sub?", name: "sub12", expected: false},
+		{pattern: "*.txt", name: "a.b.txt", expected: true},
+		{pattern: "*.txt", name: "a.txt.bak", expected: false},
+		{pattern: "a*b*c", name: "abxbc", expected: true},
+		{pattern: "a*b*c", name: "abcx", expected: false},
+		{pattern: "*", name: "", expected: true},
+		{pattern: "?", name: "ż", expected: true},
+		{pattern: "SUB*", name: "sub1", expected: false},
+	}
+
+	for _, tt := range tests {
+		if matched := MatchWildcard(tt.pattern, tt.name); matched != tt.expected {
+			t.Fatalf("match of %q against %q: want %v, got %v", tt.name, tt.pattern, tt.expected, matched)
+		}
+	}
+}
+
+func TestExpandWildcards(t *testing.T) {
+	t.Parallel()
+	fs := New()
+	fs.AddSubdir("sub2")
+	fs.AddSubdir("sub1\\sub3")
+	fs.Touch("sub1.txt")
+	fs.Touch("other.txt")
+	fs.Cd("sub1")
+
+	tests := []struct {
+		path        string
+		expected    []string
+		expectedErr error
+	}{
+		{path: "sub3", expected: []string{"sub3"}},
+		{path: "..\\sub*", expected: []string{"..\\sub1", "..\\sub1.txt", "..\\sub2"}},
+		{path: "\\*.txt", expected: []string{"\\other.txt", "\\sub1.txt"}},
+		{path: "root\\sub?\\", expected: []string{"root\\sub1", "root\\sub2"}},
+		{path: "sub*", expected: []string{".\\sub3"}},
+		{path: "x*", expectedErr: ErrNoMatch},
+		{path: "..\\sub*\\sub3", expectedErr: ErrInvalidPath},
+		{path: "sub4\\*", expectedErr: ErrSubdirDoesNotExist},
+	}
+
+	for _, tt := range tests {
+		matches, err := fs.ExpandWildcards(tt.path)
+		if !errors.Is(err, tt.expectedErr) {
+			t.Fatalf("error of %q mismatch:\nwant: %v\ngot: %v", tt.path, tt.expectedErr, err)
+		}
+		if diff := cmp.Diff(tt.expected, matches); diff != "" {
+			t.Fatalf("matches of %q mismatch (-want +got):\n%s", tt.path, diff)
+		}
+	}
+
+	if err := fs.AddSubdir("sub*"); !errors.Is(err, ErrInvalidPath) {
+		t.Fatalf("name with wildcards should be invalid, got: %v", err)
+	}
+}
+
+func TestResolvePattern(t *testing.T) {
+	t.Parallel()
+	fs := New()
+	fs.AddSubdir("sub2")
+	fs.AddSubdir("sub1\\sub3")
+	fs.Touch("sub1.txt")
+	fs.Cd("sub1")
+
+	tests := []struct {
+		path            string
+		expectedDir     string
+		expectedPattern string
+		expectedErr     error
+	}{
+		{path: "sub*", expectedDir: "root\\sub1", expectedPattern: "sub*"},
+		{path: "..\\sub*", expectedDir: "root", expectedPattern: "sub*"},
+		{path: "\\*.txt", expectedDir: "root", expectedPattern: "*.txt"},
+		{path: "root\\sub?\\", expectedDir: "root", expectedPattern: "sub?"},
+		{path: "x*", expectedErr: ErrNoMatch},
+		{path: "..\\sub*\\sub3", expectedErr: ErrInvalidPath},
+		{path: "sub4\\*", expectedErr: ErrSubdirDoesNotExist},
+	}
+
+	for _, tt := range tests {
+		dir, pattern, err := fs.ResolvePattern(tt.path)
+		if !errors.Is(err, tt.expectedErr) {
+			t.Fatalf("error of %q mismatch:\nwant: %v\ngot: %v", tt.path, tt.expectedErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		if dir.Path() != tt.expectedDir || pattern != tt.expectedPattern {
+			t.Fatalf("pattern of %q mismatch:\nwant: %s, %s\ngot: %s, %s", tt.path, tt.expectedDir, tt.expectedPattern, dir.Path(), pattern)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package vfs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	iofs "io/fs"
+	"os"
+	"strings"
+	"time"
+)
+
+// writable filesystem with semantics of the functions of package os, implemented by *Filesystem
+// names are separated by slashes and relative to root, as in io/fs
+// permissions are not simulated, perm arguments are ignored
+// errors are *fs.PathError, or *os.LinkError for Rename, wrapping errors of this package
+type WritableFS interface {
+	iofs.StatFS
+	Mkdir(name string, perm iofs.FileMode) error
+	MkdirAll(name string, perm iofs.FileMode) error
+	Remove(name string) error
+	RemoveAll(name string) error
+	Rename(oldname, newname string) error
+	Create(name string) (WritableFile, error)
+	OpenFile(name string, flag int, perm iofs.FileMode) (WritableFile, error)
+}
+
+// file opened for reading and writing, *os.File also implements it
+type WritableFile interface {
+	iofs.File
+	io.Writer
+	io.Seeker
+}
+
+// creates directory, its parent must exist
+func (fs *Filesystem) Mkdir(name string, perm iofs.FileMode) error {
+	parent, base, err := fs.lookupParent(name)
+	if err != nil {
+		return &iofs.PathError{Op: "mkdir", Path: name, Err: err}
+	}
+	if !isValidName(base) {
+		return &iofs.PathError{Op: "mkdir", Path: name, Err: ErrInvalidPath}
+	}
+	if err := parent.checkNameFree(base); err != nil {
+		return &iofs.PathError{Op: "mkdir", Path: name, Err: err}
+	}
+	parent.addSubdir(&Dir{name: base, modTime: fs.now()})
+	return nil
+}
+
+// creates directory with all missing parents
+// does nothing if the directory already exists
+func (fs *Filesystem) MkdirAll(name string, perm iofs.FileMode) error {
+	if !iofs.ValidPath(name) {
+		return &iofs.PathError{Op: "mkdir", Path: name, Err: ErrInvalidPath}
+	}
+	if name == "." {
+		return nil
+	}
+	current := fs.root
+	for _, step := range strings.Split(name, "/") {
+		if sub := current.findSub(step); sub != nil {
+			current = sub
+			continue
+		}
+		if !isValidName(step) {
+			return &iofs.PathError{Op: "mkdir", Path: name, Err: ErrInvalidPath}
+		}
+		if err := current.checkNameFree(step); err != nil {
+			return &iofs.PathError{Op: "mkdir", Path: name, Err: err}
+		}
+		sub := &Dir{name: step, modTime: fs.now()}
+		current.addSubdir(sub)
+		current = sub
+	}
+	return nil
+}
+
+// removes file or empty directory
+// current directory and its parents cannot be removed
+func (fs *Filesystem) Remove(name string) error {
+	parent, base, err := fs.lookupParent(name)
+	if err != nil {
+		return &iofs.PathError{Op: "remove", Path: name, Err: err}
+	}
+	if f := parent.findFile(base); f != nil {
+		parent.removeFile(f)
+		return nil
+	}
+	target := parent.findSub(base)
+	if target == nil {
+		return &iofs.PathError{Op: "remove", Path: name, Err: ErrFileDoesNotExist}
+	}
+	if err := fs.removeDir(target, false); err != nil {
+		return &iofs.PathError{Op: "remove", Path: name, Err: err}
+	}
+	return nil
+}
+
+// removes file or directory with all its content
+// does nothing if it doesn't exist
+// current directory and its parents cannot be removed
+func (fs *Filesystem) RemoveAll(name string) error {
+	parent, base, err := fs.lookupParent(name)
+	if errors.Is(err, ErrSubdirDoesNotExist) {
+		return nil
+	}
+	if err != nil {
+		return &iofs.PathError{Op: "removeall", Path: name, Err: err}
+	}
+	if f := parent.findFile(base); f != nil {
+		parent.removeFile(f)
+		return nil
+	}
+	target := parent.findSub(base)
+	if target == nil {
+		return nil
+	}
+	if err := fs.removeDir(target, true); err != nil {
+		return &iofs.PathError{Op: "removeall", Path: name, Err: err}
+	}
+	return nil
+}
+
+// renames or moves file or directory, the parent of the new name must exist
+// existing file is replaced by a file, other existing entries are never replaced
+func (fs *Filesystem) Rename(oldname, newname string) error {
+	linkErr := func(err error) error {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	oldParent, oldBase, err := fs.lookupParent(oldname)
+	if err != nil {
+		return linkErr(err)
+	}
+	srcDir := oldParent.findSub(oldBase)
+	srcFile := oldParent.findFile(oldBase)
+	if srcDir == nil && srcFile == nil {
+		return linkErr(ErrFileDoesNotExist)
+	}
+	newParent, newBase, err := fs.lookupParent(newname)
+	if err != nil {
+		return linkErr(err)
+	}
+	if !isValidName(newBase) {
+		return linkErr(ErrInvalidPath)
+	}
+	if srcDir != nil && srcDir.contains(newParent) {
+		return linkErr(ErrMoveIntoItself)
+	}
+	if oldParent == newParent && oldBase == newBase {
+		return nil
+	}
+
+	if existing := newParent.findFile(newBase); existing != nil && srcFile != nil {
+		newParent.removeFile(existing)
+	} else if err := newParent.checkNameFree(newBase); err != nil {
+		return linkErr(err)
+	}
+	if srcFile != nil {
+		oldParent.removeFile(srcFile)
+		srcFile.name = newBase
+		newParent.addFile(srcFile)
+		return nil
+	}
+	moveDirectory(srcDir, newParent, newBase)
+	return nil
+}
+
+// creates or truncates the file and opens it for reading and writing
+func (fs *Filesystem) Create(name string) (WritableFile, error) {
+	return fs.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+// opens the file with flags of package os, e.g. os.O_WRONLY|os.O_APPEND
+// only files can be opened, directories can be read with Open
+func (fs *Filesystem) OpenFile(name string, flag int, perm iofs.FileMode) (WritableFile, error) {
+	parent, base, err := fs.lookupParent(name)
+	if err != nil {
+		return nil, &iofs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if parent.findSub(base) != nil {
+		return nil, &iofs.PathError{Op: "open", Path: name, Err: iofs.ErrInvalid}
+	}
+
+	f := parent.findFile(base)
+	switch {
+	case f == nil && flag&os.O_CREATE == 0:
+		return nil, &iofs.PathError{Op: "open", Path: name, Err: ErrFileDoesNotExist}
+	case f == nil:
+		if !isValidName(base) {
+			return nil, &iofs.PathError{Op: "open", Path: name, Err: ErrInvalidPath}
+		}
+		f = &File{name: base, modTime: fs.now()}
+		parent.addFile(f)
+	case flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		return nil, &iofs.PathError{Op: "open", Path: name, Err: ErrFileAlreadyExists}
+	}
+
+	opened := &writableFile{file: f, flag: flag, clock: fs.clock}
+	if flag&os.O_TRUNC != 0 && opened.canWrite() {
+		f.content = nil
+		f.modTime = fs.now()
+	}
+	return opened, nil
+}
+
+// resolves all but the last element of the name
+// returns directory containing the last element and its name
+func (fs *Filesystem) lookupParent(name string) (*Dir, string, error) {
+	if !iofs.ValidPath(name) || name == "." {
+		return nil, "", ErrInvalidPath
+	}
+	steps := strings.Split(name, "/")
+	parent := fs.root
+	for _, step := range steps[:len(steps)-1] {
+		parent = parent.findSub(step)
+		if parent == nil {
+			return nil, "", ErrSubdirDoesNotExist
+		}
+	}
+	return parent, steps[len(steps)-1], nil
+}
+
+// file opened by OpenFile, reads and writes go directly to the simulated file
+type writableFile struct {
+	file   *File
+	flag   int
+	offset int64
+	closed bool
+	// clock of the filesystem, sets modification time of written file
+	clock func() time.Time
+}
+
+func (f *writableFile) canRead() bool {
+	return f.flag&(os.O_RDONLY|os.O_WRONLY|os.O_RDWR) != os.O_WRONLY
+}
+
+func (f *writableFile) canWrite() bool {
+	return f.flag&(os.O_RDONLY|os.O_WRONLY|os.O_RDWR) != os.O_RDONLY
+}
+
+func (f *writableFile) Stat() (iofs.FileInfo, error) {
+	if f.closed {
+		return nil, &iofs.PathError{Op: "stat", Path: f.file.name, Err: iofs.ErrClosed}
+	}
+	return fileInfo{name: f.file.name, file: f.file}, nil
+}
+
+func (f *writableFile) Read(b []byte) (int, error) {
+	if f.closed {
+		return 0, &iofs.PathError{Op: "read", Path: f.file.name, Err: iofs.ErrClosed}
+	}
+	if !f.canRead() {
+		return 0, &iofs.PathError{Op: "read", Path: f.file.name, Err: iofs.ErrPermission}
+	}
+	if f.offset >= int64(len(f.file.content)) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.file.content[f.offset:])
+	f.offset += int64(n)
+	return n, nil
+}
+
+// writes at the offset, with os.O_APPEND always at the end of the file
+// writing past the end fills the gap with zeros
+func (f *writableFile) Write(b []byte) (int, error) {
+	if f.closed {
+		return 0, &iofs.PathError{Op: "write", Path: f.file.name, Err: iofs.ErrClosed}
+	}
+	if !f.canWrite() {
+		return 0, &iofs.PathError{Op: "write", Path: f.file.name, Err: iofs.ErrPermission}
+	}
+	content := f.file.content
+	if f.flag&os.O_APPEND != 0 {
+		f.offset = int64(len(content))
+	}
+	if f.offset < int64(len(content)) {
+		// content may be shared with readers opened before, it is never modified in place
+		content = bytes.Clone(content)
+	}
+	if gap := f.offset - int64(len(content)); gap > 0 {
+		content = append(content, make([]byte, gap)...)
+	}
+	n := copy(content[f.offset:], b)
+	f.file.content = append(content, b[n:]...)
+	f.file.modTime = f.clock()
+	f.offset += int64(len(b))
+	return len(b), nil
+}
+
+func (f *writableFile) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, &iofs.PathError{Op: "seek", Path: f.file.name, Err: iofs.ErrClosed}
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += int64(len(f.file.content))
+	default:
+		return 0, &iofs.PathError{Op: "seek", Path: f.file.name, Err: iofs.ErrInvalid}
+	}
+	if offset < 0 {
+		return 0, &iofs.PathError{Op: "seek", Path: f.file.name, Err: iofs.ErrInvalid}
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *writableFile) Close() error {
+	if f.closed {
+		return &iofs.PathError{Op: "close", Path: f.file.name, Err: iofs.ErrClosed}
+	}
+	f.closed = true
+	return nil
+}
@@ -0,0 +1,271 @@
+package vfs
+
+import (
+	"errors"
+	"io"
+	iofs "io/fs"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var _ WritableFS = (*Filesystem)(nil)
+
+func TestErrorsMatchStandardErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		err      error
+		expected error
+	}{
+		{err: ErrSubdirDoesNotExist, expected: iofs.ErrNotExist},
+		{err: ErrFileDoesNotExist, expected: iofs.ErrNotExist},
+		{err: ErrSubdirAlreadyExists, expected: iofs.ErrExist},
+		{err: ErrFileAlreadyExists, expected: iofs.ErrExist},
+		{err: ErrSubdirNotEmpty, expected: iofs.ErrExist},
+		{err: ErrInvalidPath, expected: iofs.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.expected) {
+			t.Fatalf("%v doesn't match %v", tt.err, tt.expected)
+		}
+	}
+}
+
+func TestWritableFS(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		call         func(fs *Filesystem) error
+		expectedErr  error
+		expectedTree []string
+	}{
+		{
+			name: "mkdir",
+			call: func(fs *Filesystem) error {
+				return fs.Mkdir("sub1/sub4", 0755)
+			},
+			expectedTree: []string{"a.txt", "sub1/", "sub1/b.txt", "sub1/sub3/", "sub1/sub3/empty.txt", "sub1/sub4/", "sub2/"},
+		},
+		{
+			name: "mkdir without parent",
+			call: func(fs *Filesystem) error {
+				return fs.Mkdir("sub4/sub5", 0755)
+			},
+			expectedErr: iofs.ErrNotExist,
+		},
+		{
+			name: "mkdir existing",
+			call: func(fs *Filesystem) error {
+				return fs.Mkdir("a.txt", 0755)
+			},
+			expectedErr: ErrFileAlreadyExists,
+		},
+		{
+			name: "mkdir with backslash",
+			call: func(fs *Filesystem) error {
+				return fs.Mkdir("sub4\\sub5", 0755)
+			},
+			expectedErr: ErrInvalidPath,
+		},
+		{
+			name: "mkdir all",
+			call: func(fs *Filesystem) error {
+				return fs.MkdirAll("sub1/sub4/sub5", 0755)
+			},
+			expectedTree: []string{"a.txt", "sub1/", "sub1/b.txt", "sub1/sub3/", "sub1/sub3/empty.txt", "sub1/sub4/", "sub1/sub4/sub5/", "sub2/"},
+		},
+		{
+			name: "mkdir all existing",
+			call: func(fs *Filesystem) error {
+				return fs.MkdirAll("sub1/sub3", 0755)
+			},
+		},
+		{
+			name: "mkdir all through file",
+			call: func(fs *Filesystem) error {
+				return fs.MkdirAll("a.txt/sub4", 0755)
+			},
+			expectedErr: iofs.ErrExist,
+		},
+		{
+			name: "remove file",
+			call: func(fs *Filesystem) error {
+				return fs.Remove("sub1/b.txt")
+			},
+			expectedTree: []string{"a.txt", "sub1/", "sub1/sub3/", "sub1/sub3/empty.txt", "sub2/"},
+		},
+		{
+			name: "remove empty directory",
+			call: func(fs *Filesystem) error {
+				return fs.Remove("sub2")
+			},
+			expectedTree: []string{"a.txt", "sub1/", "sub1/b.txt", "sub1/sub3/", "sub1/sub3/empty.txt"},
+		},
+		{
+			name: "remove directory that is not empty",
+			call: func(fs *Filesystem) error {
+				return fs.Remove("sub1")
+			},
+			expectedErr: ErrSubdirNotEmpty,
+		},
+		{
+			name: "remove missing",
+			call: func(fs *Filesystem) error {
+				return fs.Remove("sub1/c.txt")
+			},
+			expectedErr: iofs.ErrNotExist,
+		},
+		{
+			name: "remove root",
+			call: func(fs *Filesystem) error {
+				return fs.Remove(".")
+			},
+			expectedErr: iofs.ErrInvalid,
+		},
+		{
+			name: "remove all",
+			call: func(fs *Filesystem) error {
+				return fs.RemoveAll("sub1")
+			},
+			expectedTree: []string{"a.txt", "sub2/"},
+		},
+		{
+			name: "remove all missing",
+			call: func(fs *Filesystem) error {
+				return fs.RemoveAll("sub4/sub5")
+			},
+		},
+		{
+			name: "remove all with current directory",
+			call: func(fs *Filesystem) error {
+				fs.Cd("sub1\\sub3")
+				return fs.RemoveAll("sub1")
+			},
+			expectedErr: ErrSubdirInUse,
+		},
+		{
+			name: "rename file",
+			call: func(fs *Filesystem) error {
+				return fs.Rename("a.txt", "sub2/c.txt")
+			},
+			expectedTree: []string{"sub1/", "sub1/b.txt", "sub1/sub3/", "sub1/sub3/empty.txt", "sub2/", "sub2/c.txt"},
+		},
+		{
+			name: "rename file replaces file",
+			call: func(fs *Filesystem) error {
+				return fs.Rename("a.txt", "sub1/b.txt")
+			},
+			expectedTree: []string{"sub1/", "sub1/b.txt", "sub1/sub3/", "sub1/sub3/empty.txt", "sub2/"},
+		},
+		{
+			name: "rename directory",
+			call: func(fs *Filesystem) error {
+				return fs.Rename("sub1", "sub2/sub4")
+			},
+			expectedTree: []string{"a.txt", "sub2/", "sub2/sub4/", "sub2/sub4/b.txt", "sub2/sub4/sub3/", "sub2/sub4/sub3/empty.txt"},
+		},
+		{
+			name: "rename directory onto existing",
+			call: func(fs *Filesystem) error {
+				return fs.Rename("sub2", "sub1")
+			},
+			expectedErr: ErrSubdirAlreadyExists,
+		},
+		{
+			name: "rename directory into itself",
+			call: func(fs *Filesystem) error {
+				return fs.Rename("sub1", "sub1/sub3/sub4")
+			},
+			expectedErr: ErrMoveIntoItself,
+		},
+		{
+			name: "rename missing",
+			call: func(fs *Filesystem) error {
+				return fs.Rename("c.txt", "d.txt")
+			},
+			expectedErr: iofs.ErrNotExist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := createFSFixture()
+			fs.Cd("\\")
+			err := tt.call(fs)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("error mismatch:\nwant: %v\ngot: %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				var pathErr *iofs.PathError
+				var linkErr *os.LinkError
+				if !errors.As(err, &pathErr) && !errors.As(err, &linkErr) {
+					t.Fatalf("expected *fs.PathError or *os.LinkError, got: %T", err)
+				}
+				return
+			}
+			if tt.expectedTree == nil {
+				return
+			}
+			if diff := cmp.Diff(tt.expectedTree, listTree(fs)); diff != "" {
+				t.Fatalf("tree mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWritableFile(t *testing.T) {
+	t.Parallel()
+	fs := New()
+	f, err := fs.Create("a.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	io.WriteString(f, "hello world")
+	f.Seek(6, io.SeekStart)
+	io.WriteString(f, "there")
+	f.Seek(0, io.SeekStart)
+	content, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "hello there" {
+		t.Fatalf("content mismatch:\nwant: %q\ngot: %q", "hello there", content)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := f.Write([]byte("!")); !errors.Is(err, iofs.ErrClosed) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", iofs.ErrClosed, err)
+	}
+
+	f, err = fs.OpenFile("a.txt", os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.Seek(0, io.SeekStart)
+	io.WriteString(f, "!\n")
+	if _, err := f.Read(make([]byte, 1)); !errors.Is(err, iofs.ErrPermission) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", iofs.ErrPermission, err)
+	}
+	f.Close()
+
+	content, err = fs.ReadFile("a.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "hello there!\n" {
+		t.Fatalf("content mismatch:\nwant: %q\ngot: %q", "hello there!\n", content)
+	}
+
+	if _, err := fs.OpenFile("a.txt", os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644); !errors.Is(err, iofs.ErrExist) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", iofs.ErrExist, err)
+	}
+	if _, err := fs.OpenFile("b.txt", os.O_RDONLY, 0); !errors.Is(err, iofs.ErrNotExist) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", iofs.ErrNotExist, err)
+	}
+	if _, err := fs.Create("b.txt/c.txt"); !errors.Is(err, iofs.ErrNotExist) {
+		t.Fatalf("error mismatch:\nwant: %v\ngot: %v", iofs.ErrNotExist, err)
+	}
+}